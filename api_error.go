@@ -0,0 +1,91 @@
+package wexapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrorCode classifies errors returned by wex api.
+// Codes can be matched against errors returned
+// by the client with errors.Is.
+type ErrorCode int
+
+// Available error codes.
+const (
+	ErrUnknown ErrorCode = iota
+	ErrInsufficientFunds
+	ErrInvalidNonce
+	ErrNoPermission
+	ErrInvalidPair
+	ErrOrderNotFound
+)
+
+var errorCodeNames = map[ErrorCode]string{
+	ErrUnknown:           "unknown error",
+	ErrInsufficientFunds: "insufficient funds",
+	ErrInvalidNonce:      "invalid nonce",
+	ErrNoPermission:      "no permission",
+	ErrInvalidPair:       "invalid pair",
+	ErrOrderNotFound:     "order not found",
+}
+
+func (code ErrorCode) Error() string {
+	if name, ok := errorCodeNames[code]; ok {
+		return name
+	}
+	return fmt.Sprintf("error code %d", int(code))
+}
+
+// errorPatterns maps lower cased fragments of the
+// server messages to the error codes.
+var errorPatterns = []struct {
+	fragment string
+	code     ErrorCode
+}{
+	{fragment: "not enough", code: ErrInsufficientFunds},
+	{fragment: "insufficient funds", code: ErrInsufficientFunds},
+	{fragment: "invalid nonce", code: ErrInvalidNonce},
+	{fragment: "permission", code: ErrNoPermission},
+	{fragment: "invalid api key", code: ErrNoPermission},
+	{fragment: "invalid pair", code: ErrInvalidPair},
+	{fragment: "invalid order", code: ErrOrderNotFound},
+	{fragment: "order not found", code: ErrOrderNotFound},
+}
+
+func classifyError(message string) ErrorCode {
+	lower := strings.ToLower(message)
+	for _, pattern := range errorPatterns {
+		if strings.Contains(lower, pattern.fragment) {
+			return pattern.code
+		}
+	}
+	return ErrUnknown
+}
+
+// APIError is an error returned by the server
+// in the response body.
+type APIError struct {
+	Method     string
+	Message    string
+	StatusCode int
+	Code       ErrorCode
+}
+
+func newAPIError(method, message string, statusCode int) *APIError {
+	return &APIError{
+		Method:     method,
+		Message:    message,
+		StatusCode: statusCode,
+		Code:       classifyError(message),
+	}
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("server respond with error: %s", e.Message)
+}
+
+// Is reports whether target is the error code of e.
+func (e *APIError) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && code == e.Code
+}
@@ -0,0 +1,85 @@
+package wexapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func Test_classifyError(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    ErrorCode
+	}{
+		{
+			name:    "insufficient funds",
+			message: "It is not enough BTC in the account for sale.",
+			want:    ErrInsufficientFunds,
+		},
+		{
+			name:    "invalid nonce",
+			message: "invalid nonce parameter; on key:1500000000, you sent:'1400000000', you should send:1500000001",
+			want:    ErrInvalidNonce,
+		},
+		{
+			name:    "no permission",
+			message: "api key dont have trade permission",
+			want:    ErrNoPermission,
+		},
+		{
+			name:    "invalid pair",
+			message: "Invalid pair name: btcusd",
+			want:    ErrInvalidPair,
+		},
+		{
+			name:    "order not found",
+			message: "invalid order",
+			want:    ErrOrderNotFound,
+		},
+		{
+			name:    "unknown",
+			message: "Invalid method",
+			want:    ErrUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyError(tt.message); got != tt.want {
+				t.Errorf("classifyError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"success":0,"error":"It is not enough BTC in the account for sale."}`)
+	}))
+	defer server.Close()
+	httpClient := testingHTTPClient(server)
+
+	cli := NewClient("", "", SetHTTPClient(httpClient))
+	_, err := cli.Trade(NewPair("btc", "usd"), Sell, decimal.Zero, decimal.Zero)
+
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("errors.Is(%v, ErrInsufficientFunds) = false, want true", err)
+	}
+	if errors.Is(err, ErrInvalidNonce) {
+		t.Errorf("errors.Is(%v, ErrInvalidNonce) = true, want false", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("errors.As(%v, *APIError) = false, want true", err)
+	}
+	if apiErr.Method != "Trade" {
+		t.Errorf("APIError.Method = %s, want Trade", apiErr.Method)
+	}
+	if apiErr.StatusCode != http.StatusOK {
+		t.Errorf("APIError.StatusCode = %d, want %d", apiErr.StatusCode, http.StatusOK)
+	}
+}
@@ -0,0 +1,181 @@
+package wexapi
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Candle holds OHLCV data of the interval started at Start.
+// Candles of the intervals without trades are flat at the
+// previous close with zero volume.
+type Candle struct {
+	Start  time.Time
+	Open   decimal.Decimal
+	High   decimal.Decimal
+	Low    decimal.Decimal
+	Close  decimal.Decimal
+	Volume decimal.Decimal
+	Trades int
+}
+
+// candleState is a candle with time and ID of its
+// first and last trades to handle unordered trades.
+type candleState struct {
+	Candle
+
+	firstTime, lastTime time.Time
+	firstID, lastID     uint64
+}
+
+// CandleBuilder aggregates trades into candles of the interval.
+// Trades may come in any order, but must not repeat, use
+// TradeStream to get them without repeats.
+// Use NewCandleBuilder to initialize one.
+type CandleBuilder struct {
+	interval time.Duration
+
+	mu      sync.Mutex
+	candles map[int64]*candleState
+}
+
+// NewCandleBuilder returns builder of the candles of the
+// interval, e.g. time.Minute or 24 * time.Hour. Intervals
+// are aligned to UTC. It panics if interval isn't positive.
+func NewCandleBuilder(interval time.Duration) *CandleBuilder {
+	if interval <= 0 {
+		panic("wexapi: non-positive interval for NewCandleBuilder")
+	}
+
+	return &CandleBuilder{
+		interval: interval,
+		candles:  make(map[int64]*candleState),
+	}
+}
+
+// Add aggregates trades into candles.
+func (b *CandleBuilder) Add(trades ...Trade) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, trade := range trades {
+		b.add(time.Time(trade.Timestamp), trade.ID, trade.Rate, trade.Rate, trade.Rate, trade.Amount, 1)
+	}
+}
+
+// Seed adds market snapshot to the candle of its update time.
+// Last price becomes open or close of the candle if it's before
+// or after its trades. Market high and low are of the last 24
+// hours, so they're used for daily candles only. Volume isn't
+// added, the candle gets it from the trades. Market without
+// update time or last price, e.g. zero one, is ignored.
+func (b *CandleBuilder) Seed(market Market) {
+	if time.Time(market.Updated).IsZero() || market.Last.IsZero() {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	high, low := market.Last, market.Last
+	if b.interval == 24*time.Hour {
+		high, low = market.High, market.Low
+	}
+	b.add(time.Time(market.Updated), 0, market.Last, high, low, decimal.Zero, 0)
+}
+
+// Run adds trades from the events until events
+// channel is closed or ctx is done.
+func (b *CandleBuilder) Run(ctx context.Context, events <-chan TradeStreamEvent) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			b.Add(event.Trades...)
+		}
+	}
+}
+
+func (b *CandleBuilder) add(at time.Time, id uint64, price, high, low, volume decimal.Decimal, trades int) {
+	start := at.Truncate(b.interval)
+	state, ok := b.candles[start.UnixNano()]
+	if !ok {
+		b.candles[start.UnixNano()] = &candleState{
+			Candle: Candle{
+				Start:  start.UTC(),
+				Open:   price,
+				High:   high,
+				Low:    low,
+				Close:  price,
+				Volume: volume,
+				Trades: trades,
+			},
+			firstTime: at,
+			lastTime:  at,
+			firstID:   id,
+			lastID:    id,
+		}
+		return
+	}
+
+	if at.Before(state.firstTime) || (at.Equal(state.firstTime) && id < state.firstID) {
+		state.Open, state.firstTime, state.firstID = price, at, id
+	}
+	if at.After(state.lastTime) || (at.Equal(state.lastTime) && id > state.lastID) {
+		state.Close, state.lastTime, state.lastID = price, at, id
+	}
+	state.High = decimal.Max(state.High, high)
+	state.Low = decimal.Min(state.Low, low)
+	state.Volume = state.Volume.Add(volume)
+	state.Trades += trades
+}
+
+// Candles returns candles from the first to the last
+// trade sorted by start time, empty intervals included.
+func (b *CandleBuilder) Candles() []Candle {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.candles) == 0 {
+		return nil
+	}
+
+	starts := make([]int64, 0, len(b.candles))
+	for start := range b.candles {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool {
+		return starts[i] < starts[j]
+	})
+
+	first := time.Unix(0, starts[0])
+	last := time.Unix(0, starts[len(starts)-1])
+
+	var candles []Candle
+	for start := first; !start.After(last); start = start.Add(b.interval) {
+		state, ok := b.candles[start.UnixNano()]
+		if ok {
+			candles = append(candles, state.Candle)
+			continue
+		}
+
+		prev := candles[len(candles)-1].Close
+		candles = append(candles, Candle{
+			Start:  start.UTC(),
+			Open:   prev,
+			High:   prev,
+			Low:    prev,
+			Close:  prev,
+			Volume: decimal.Zero,
+		})
+	}
+
+	return candles
+}
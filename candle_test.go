@@ -0,0 +1,182 @@
+package wexapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func newTestTrade(id uint64, at int64, rate, amount string) Trade {
+	return Trade{
+		ID:        id,
+		Type:      Buy,
+		Rate:      decimal.RequireFromString(rate),
+		Amount:    decimal.RequireFromString(amount),
+		Timestamp: unixTimestamp(time.Unix(at, 0)),
+	}
+}
+
+func formatCandles(candles []Candle) []string {
+	result := make([]string, len(candles))
+	for i, c := range candles {
+		result[i] = fmt.Sprintf("%d %s %s %s %s %s %d", c.Start.Unix(), c.Open, c.High, c.Low, c.Close, c.Volume, c.Trades)
+	}
+	return result
+}
+
+func TestCandleBuilder(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		trades   []Trade
+		market   *Market
+		want     []string
+	}{
+		{
+			name:     "one minute",
+			interval: time.Minute,
+			trades: []Trade{
+				newTestTrade(3, 130, "103", "1"),
+				newTestTrade(1, 120, "100", "1"),
+				newTestTrade(2, 125, "99", "2"),
+				newTestTrade(4, 170, "101", "0.5"),
+			},
+			want: []string{
+				"120 100 103 99 101 4.5 4",
+			},
+		},
+		{
+			name:     "empty intervals",
+			interval: time.Minute,
+			trades: []Trade{
+				newTestTrade(1, 60, "100", "1"),
+				newTestTrade(2, 70, "102", "1"),
+				newTestTrade(3, 250, "98", "1"),
+			},
+			want: []string{
+				"60 100 102 100 102 2 2",
+				"120 102 102 102 102 0 0",
+				"180 102 102 102 102 0 0",
+				"240 98 98 98 98 1 1",
+			},
+		},
+		{
+			name:     "same time ordered by id",
+			interval: 5 * time.Minute,
+			trades: []Trade{
+				newTestTrade(2, 300, "101", "1"),
+				newTestTrade(1, 300, "100", "1"),
+			},
+			want: []string{
+				"300 100 101 100 101 2 2",
+			},
+		},
+		{
+			name:     "seeded from market",
+			interval: 24 * time.Hour,
+			market: &Market{
+				High:             decimal.RequireFromString("110"),
+				Low:              decimal.RequireFromString("90"),
+				Last:             decimal.RequireFromString("100"),
+				VolumeInCurrency: decimal.RequireFromString("50"),
+				Updated:          unixTimestamp(time.Unix(86400+3600, 0)),
+			},
+			trades: []Trade{
+				newTestTrade(1, 86400+7200, "115", "1"),
+			},
+			want: []string{
+				"86400 100 115 90 115 1 1",
+			},
+		},
+		{
+			name:     "seeded hourly",
+			interval: time.Hour,
+			market: &Market{
+				High:             decimal.RequireFromString("110"),
+				Low:              decimal.RequireFromString("90"),
+				Last:             decimal.RequireFromString("100"),
+				VolumeInCurrency: decimal.RequireFromString("50"),
+				Updated:          unixTimestamp(time.Unix(3600+60, 0)),
+			},
+			trades: []Trade{
+				newTestTrade(1, 3600+30, "99", "1"),
+				newTestTrade(2, 3600+120, "101", "2"),
+			},
+			want: []string{
+				"3600 99 101 99 101 3 2",
+			},
+		},
+		{
+			name:     "seeded from zero market",
+			interval: time.Minute,
+			market:   &Market{},
+			trades: []Trade{
+				newTestTrade(1, 60, "100", "1"),
+			},
+			want: []string{
+				"60 100 100 100 100 1 1",
+			},
+		},
+		{
+			name:     "sub second interval",
+			interval: 500 * time.Millisecond,
+			trades: []Trade{
+				newTestTrade(1, 60, "100", "1"),
+				newTestTrade(2, 61, "101", "1"),
+			},
+			want: []string{
+				"60 100 100 100 100 1 1",
+				"60 100 100 100 100 0 0",
+				"61 101 101 101 101 1 1",
+			},
+		},
+		{
+			name:     "no trades",
+			interval: time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewCandleBuilder(tt.interval)
+			if tt.market != nil {
+				builder.Seed(*tt.market)
+			}
+			builder.Add(tt.trades...)
+
+			got := formatCandles(builder.Candles())
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("CandleBuilder.Candles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCandleBuilder_invalidInterval(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewCandleBuilder(0) didn't panic")
+		}
+	}()
+	NewCandleBuilder(0)
+}
+
+func TestCandleBuilder_Run(t *testing.T) {
+	events := make(chan TradeStreamEvent, 2)
+	events <- TradeStreamEvent{Trades: []Trade{newTestTrade(1, 60, "100", "1")}}
+	events <- TradeStreamEvent{Trades: []Trade{newTestTrade(2, 61, "101", "1")}}
+	close(events)
+
+	builder := NewCandleBuilder(time.Minute)
+	if err := builder.Run(context.Background(), events); err != nil {
+		t.Fatalf("CandleBuilder.Run() error = %v", err)
+	}
+
+	got := formatCandles(builder.Candles())
+	want := []string{"60 100 101 100 101 2 2"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("CandleBuilder.Candles() = %v, want %v", got, want)
+	}
+}
@@ -2,9 +2,12 @@ package wexapi
 
 import (
 	"errors"
-	"math"
+	"log/slog"
 	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,6 +19,10 @@ const (
 // which is maximum size for api key at wex.
 var ErrNonceOverflow = errors.New("max value reached: create new key")
 
+// nonceKeyPattern matches the last nonce used on the key
+// in the server invalid nonce error message.
+var nonceKeyPattern = regexp.MustCompile(`on key:\s*(\d+)`)
+
 // Option for initializer.
 type Option func(*Client)
 
@@ -26,6 +33,15 @@ func SetHTTPClient(httpClient *http.Client) Option {
 	}
 }
 
+// SetNonceStore sets store of the nonces used for
+// trade api requests. Use FileNonceStore or own
+// implementation to share the key between processes.
+func SetNonceStore(store NonceStore) Option {
+	return func(cli *Client) {
+		cli.nonceStore = store
+	}
+}
+
 // SetTimeout sets timeout for the http client.
 func SetTimeout(timeout time.Duration) Option {
 	return func(cli *Client) {
@@ -33,13 +49,47 @@ func SetTimeout(timeout time.Duration) Option {
 	}
 }
 
+// SetPublicEndpoint sets base url of the public api,
+// e.g. "https://wex.nz/api/3".
+func SetPublicEndpoint(endpoint string) Option {
+	return func(cli *Client) {
+		cli.publicEndpoint = strings.TrimRight(endpoint, "/")
+	}
+}
+
+// SetTradeEndpoint sets url of the trade api,
+// e.g. "https://wex.nz/tapi".
+func SetTradeEndpoint(endpoint string) Option {
+	return func(cli *Client) {
+		cli.tradeEndpoint = endpoint
+	}
+}
+
 // Client for requesting wex api.
 // Use NewClient to initialize one.
 type Client struct {
 	key, secret string
 	httpClient  *http.Client
 
-	noncePool chan uint32 // max is 4294967294
+	publicEndpoint string
+	tradeEndpoint  string
+
+	nonceStore NonceStore // max is 4294967294
+
+	orderValidator *orderValidator
+
+	publicLimiter *rateLimiter
+	tradeLimiter  *rateLimiter
+
+	retryPolicy *RetryPolicy
+	middlewares []Middleware
+
+	// lastOrderID is the largest order ID
+	// seen in responses, used by PlaceOrder.
+	lastOrderID atomic.Uint64
+
+	logger          *slog.Logger
+	sensitiveParams map[string]bool
 }
 
 // NewClient returns initialized client.
@@ -50,13 +100,11 @@ func NewClient(key, secret string, options ...Option) *Client {
 		httpClient: &http.Client{
 			Timeout: defaultTimeout,
 		},
-		noncePool: make(chan uint32),
+		publicEndpoint: publicAPIEndpoint,
+		tradeEndpoint:  tradeAPIEndpoint,
+		nonceStore:     NewMemoryNonceStore(uint32(time.Now().Unix())),
 	}
 
-	go func() {
-		cli.noncePool <- uint32(time.Now().Unix())
-	}()
-
 	for _, option := range options {
 		option(&cli)
 	}
@@ -65,15 +113,29 @@ func NewClient(key, secret string, options ...Option) *Client {
 }
 
 func (cli *Client) nonce() (string, error) {
-	nonce := <-cli.noncePool
-	if nonce == uint32(math.MaxUint32)-1 {
-		go func() {
-			cli.noncePool <- nonce
-		}()
-		return "", ErrNonceOverflow
+	nonce, err := cli.nonceStore.Next()
+	if err != nil {
+		return "", err
 	}
-	go func() {
-		cli.noncePool <- nonce + 1
-	}()
 	return strconv.FormatUint(uint64(nonce), 10), nil
 }
+
+// expectedNonce parses the server invalid nonce error
+// message and returns the nonce which should be sent next.
+func expectedNonce(message string) (uint32, bool) {
+	matches := nonceKeyPattern.FindStringSubmatch(message)
+	if matches == nil {
+		return 0, false
+	}
+
+	onKey, err := strconv.ParseUint(matches[1], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+
+	if onKey >= uint64(maxNonce) {
+		return maxNonce, true
+	}
+
+	return uint32(onKey) + 1, true
+}
@@ -1,7 +1,10 @@
 package wexapi
 
 import (
+	"fmt"
 	"math"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -28,11 +31,8 @@ func TestClient_nonce(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cli := Client{
-				noncePool: make(chan uint32),
+				nonceStore: NewMemoryNonceStore(tt.nonce),
 			}
-			go func() {
-				cli.noncePool <- tt.nonce
-			}()
 			got, err := cli.nonce()
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Client.nonce() error = %v, wantErr %v", err, tt.wantErr)
@@ -44,3 +44,94 @@ func TestClient_nonce(t *testing.T) {
 		})
 	}
 }
+
+func TestSetEndpoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		options  func(url string) []Option
+		call     func(cli *Client) error
+		response string
+	}{
+		{
+			name: "public endpoint",
+			path: "/api/3/info",
+			options: func(url string) []Option {
+				return []Option{SetPublicEndpoint(url + "/api/3/")}
+			},
+			call: func(cli *Client) error {
+				_, err := cli.Info()
+				return err
+			},
+			response: infoResponse,
+		},
+		{
+			name: "trade endpoint",
+			path: "/tapi",
+			options: func(url string) []Option {
+				return []Option{SetTradeEndpoint(url + "/tapi")}
+			},
+			call: func(cli *Client) error {
+				_, err := cli.GetInfo()
+				return err
+			},
+			response: getInfoResponse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				fmt.Fprint(w, tt.response)
+			}))
+			defer server.Close()
+
+			cli := NewClient("", "", tt.options(server.URL)...)
+			if err := tt.call(cli); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotPath != tt.path {
+				t.Errorf("requested path = %s, want %s", gotPath, tt.path)
+			}
+		})
+	}
+}
+
+func Test_expectedNonce(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    uint32
+		wantOk  bool
+	}{
+		{
+			name:    "on key",
+			message: "invalid nonce parameter; on key:1500000000, you sent:'1400000000', you should send:1500000001",
+			want:    1500000001,
+			wantOk:  true,
+		},
+		{
+			name:    "overflow",
+			message: "invalid nonce parameter; on key:4294967295, you sent:'1'",
+			want:    uint32(math.MaxUint32) - 1,
+			wantOk:  true,
+		},
+		{
+			name:    "no key",
+			message: "invalid nonce parameter",
+			wantOk:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := expectedNonce(tt.message)
+			if ok != tt.wantOk {
+				t.Fatalf("expectedNonce() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if got != tt.want {
+				t.Errorf("expectedNonce() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -15,19 +15,19 @@ func main() {
 	}
 	fmt.Printf("%v\n", info)
 
-	market, err := cli.Ticker("eth_btc")
+	market, err := cli.Ticker(wexapi.NewPair("eth", "btc"))
 	if err != nil {
 		log.Fatal(err)
 	}
 	fmt.Printf("%v\n", market)
 
-	depth, err := cli.Depth("eth_btc", 2)
+	depth, err := cli.Depth(wexapi.NewPair("eth", "btc"), 2)
 	if err != nil {
 		log.Fatal(err)
 	}
 	fmt.Printf("%v\n", depth)
 
-	trades, err := cli.Trades("eth_btc", 2)
+	trades, err := cli.Trades(wexapi.NewPair("eth", "btc"), 2)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,72 @@
+package wexapi
+
+import (
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+var hundred = decimal.New(100, 0)
+
+// OrderCost holds what is spent and received
+// when the order is fully executed.
+type OrderCost struct {
+	Pair Pair
+	Side Side
+
+	// Spent is an amount of SpentCurrency paid for the order,
+	// base currency when selling and quote when buying.
+	Spent         decimal.Decimal
+	SpentCurrency string
+
+	// Gross is an amount of ReceivedCurrency before the
+	// commission is taken, Received is what is left after.
+	Gross            decimal.Decimal
+	Commission       decimal.Decimal
+	Received         decimal.Decimal
+	ReceivedCurrency string
+}
+
+// OrderCost calculates cost and proceeds of the order
+// taking the pair fee into account. Rate and amount are
+// rounded by RoundOrder first, resulting amounts are
+// truncated to 8 decimal places like balances, so
+// Received matches UserTrade.Received of executed order.
+func (info PairInfo) OrderCost(pair Pair, side Side, rate, amount decimal.Decimal) (OrderCost, error) {
+	if err := pair.Validate(); err != nil {
+		return OrderCost{}, err
+	}
+	if err := side.Validate(); err != nil {
+		return OrderCost{}, err
+	}
+
+	rate, amount = info.RoundOrder(side, rate, amount)
+	total := rate.Mul(amount).Truncate(amountDecimalPlaces)
+
+	cost := OrderCost{
+		Pair: pair,
+		Side: side,
+	}
+	if side == Buy {
+		cost.Spent, cost.SpentCurrency = total, pair.Quote
+		cost.Gross, cost.ReceivedCurrency = amount, pair.Base
+	} else {
+		cost.Spent, cost.SpentCurrency = amount, pair.Base
+		cost.Gross, cost.ReceivedCurrency = total, pair.Quote
+	}
+
+	// Fee is in percents.
+	cost.Received = cost.Gross.Sub(cost.Gross.Mul(info.Fee).Div(hundred)).Truncate(amountDecimalPlaces)
+	cost.Commission = cost.Gross.Sub(cost.Received)
+
+	return cost, nil
+}
+
+// OrderCost is like PairInfo.OrderCost but takes
+// info of the pair from the registry.
+func (r *PairRegistry) OrderCost(pair Pair, side Side, rate, amount decimal.Decimal) (OrderCost, error) {
+	info, ok := r.Lookup(pair)
+	if !ok {
+		return OrderCost{}, errors.Errorf("unknown pair %s", pair)
+	}
+	return info.OrderCost(pair, side, rate, amount)
+}
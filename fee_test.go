@@ -0,0 +1,101 @@
+package wexapi
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPairInfo_OrderCost(t *testing.T) {
+	info := PairInfo{
+		DecimalPlaces: 3,
+		MinPrice:      decimal.RequireFromString("0.1"),
+		MaxPrice:      decimal.RequireFromString("10000"),
+		MinAmount:     decimal.RequireFromString("0.001"),
+		Fee:           decimal.RequireFromString("0.2"),
+	}
+
+	tests := []struct {
+		name    string
+		side    Side
+		rate    string
+		amount  string
+		want    OrderCost
+		wantErr bool
+	}{
+		{
+			name:   "buy",
+			side:   Buy,
+			rate:   "450",
+			amount: "2",
+			want: OrderCost{
+				Spent:            decimal.RequireFromString("900"),
+				SpentCurrency:    "usd",
+				Gross:            decimal.RequireFromString("2"),
+				Commission:       decimal.RequireFromString("0.004"),
+				Received:         decimal.RequireFromString("1.996"),
+				ReceivedCurrency: "btc",
+			},
+		},
+		{
+			name:   "sell",
+			side:   Sell,
+			rate:   "450",
+			amount: "2",
+			want: OrderCost{
+				Spent:            decimal.RequireFromString("2"),
+				SpentCurrency:    "btc",
+				Gross:            decimal.RequireFromString("900"),
+				Commission:       decimal.RequireFromString("1.8"),
+				Received:         decimal.RequireFromString("898.2"),
+				ReceivedCurrency: "usd",
+			},
+		},
+		{
+			name:   "rounded to precision",
+			side:   Buy,
+			rate:   "450.0004",
+			amount: "0.123456789",
+			want: OrderCost{
+				Spent:            decimal.RequireFromString("55.555551"),
+				SpentCurrency:    "usd",
+				Gross:            decimal.RequireFromString("0.12345678"),
+				Commission:       decimal.RequireFromString("0.00024692"),
+				Received:         decimal.RequireFromString("0.12320986"),
+				ReceivedCurrency: "btc",
+			},
+		},
+		{
+			name:    "invalid side",
+			side:    Side("bid"),
+			rate:    "450",
+			amount:  "1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := info.OrderCost(NewPair("btc", "usd"), tt.side, decimal.RequireFromString(tt.rate), decimal.RequireFromString(tt.amount))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PairInfo.OrderCost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.Pair != NewPair("btc", "usd") || got.Side != tt.side {
+				t.Errorf("PairInfo.OrderCost() pair %s side %s, want btc_usd %s", got.Pair, got.Side, tt.side)
+			}
+			if !got.Spent.Equal(tt.want.Spent) || got.SpentCurrency != tt.want.SpentCurrency {
+				t.Errorf("spent %s %s, want %s %s", got.Spent, got.SpentCurrency, tt.want.Spent, tt.want.SpentCurrency)
+			}
+			if !got.Gross.Equal(tt.want.Gross) || !got.Commission.Equal(tt.want.Commission) || !got.Received.Equal(tt.want.Received) {
+				t.Errorf("gross %s commission %s received %s, want %s %s %s",
+					got.Gross, got.Commission, got.Received, tt.want.Gross, tt.want.Commission, tt.want.Received)
+			}
+			if got.ReceivedCurrency != tt.want.ReceivedCurrency {
+				t.Errorf("received currency %s, want %s", got.ReceivedCurrency, tt.want.ReceivedCurrency)
+			}
+		})
+	}
+}
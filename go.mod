@@ -0,0 +1,9 @@
+module github.com/romanyx/wexapi
+
+go 1.21
+
+require (
+	github.com/gorilla/websocket v1.4.2
+	github.com/pkg/errors v0.9.1
+	github.com/shopspring/decimal v1.3.1
+)
@@ -0,0 +1,100 @@
+package wexapi
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SetLogger sets logger of the api requests. Every request is
+// logged with its method, pairs, params, latency, status code and error,
+// successful ones at debug level and failed ones at error level.
+// Values of sensitiveParams, params marked as sensitive, e.g.
+// withdrawal addresses and coupon codes, and the key and secret
+// are redacted. Signature and nonce are never logged.
+func SetLogger(logger *slog.Logger, sensitiveParams ...string) Option {
+	return func(cli *Client) {
+		cli.logger = logger
+		cli.sensitiveParams = make(map[string]bool, len(sensitiveParams))
+		for _, key := range sensitiveParams {
+			cli.sensitiveParams[key] = true
+		}
+	}
+}
+
+// logRequests is the innermost middleware, so
+// requests are logged as they're sent.
+func (cli *Client) logRequests(next Handler) Handler {
+	return func(ctx context.Context, req *Request) (*Response, error) {
+		start := time.Now()
+		resp, err := next(ctx, req)
+		latency := time.Since(start)
+
+		var secrets []string
+		params := make([]any, 0, len(req.Params))
+		for _, param := range req.Params {
+			value := param.Value
+			if param.Sensitive || cli.sensitiveParams[param.Key] {
+				secrets = append(secrets, param.Value)
+				value = redacted
+			}
+			params = append(params, slog.String(param.Key, value))
+		}
+
+		attrs := []slog.Attr{
+			slog.String("api", req.API.String()),
+			slog.String("method", req.Method),
+		}
+		if len(req.Pairs) > 0 {
+			attrs = append(attrs, slog.String("pairs", joinPairs(req.Pairs)))
+		}
+		attrs = append(attrs,
+			slog.Group("params", params...),
+			slog.Duration("latency", latency),
+			slog.Int("status", responseStatus(resp, err)),
+		)
+
+		level := slog.LevelDebug
+		if err != nil {
+			level = slog.LevelError
+			attrs = append(attrs, slog.String("error", cli.redact(err.Error(), secrets...)))
+		}
+
+		cli.logger.LogAttrs(ctx, level, "wex api request", attrs...)
+		return resp, err
+	}
+}
+
+// redact replaces the key, secret and secrets in s,
+// e.g. sensitive params in the url of an error.
+func (cli *Client) redact(s string, secrets ...string) string {
+	for _, secret := range append(secrets, cli.key, cli.secret) {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+	return s
+}
+
+// responseStatus returns status code of the response,
+// zero if it wasn't received.
+func responseStatus(resp *Response, err error) int {
+	if resp != nil {
+		return resp.StatusCode
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode
+	}
+
+	return 0
+}
@@ -0,0 +1,82 @@
+package wexapi
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestClient_Logger(t *testing.T) {
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("method") == "RedeemCoupon" {
+			fmt.Fprint(w, `{"success":0,"error":"coupon WEXUSD-SECRET-CODE not found, key my-api-key"}`)
+			return
+		}
+		fmt.Fprint(w, withdrawResponse)
+	}))
+	defer server.Close()
+	httpClient := testingHTTPClient(server)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	cli := NewClient("my-api-key", "my-api-secret",
+		SetHTTPClient(httpClient),
+		SetLogger(logger, "coinName"),
+	)
+
+	if _, err := cli.WithdrawCoin("btc", "1SecretAddress", decimal.New(1, 0)); err != nil {
+		t.Fatalf("Client.WithdrawCoin() error = %v", err)
+	}
+	if _, err := cli.RedeemCoupon("WEXUSD-SECRET-CODE"); err == nil {
+		t.Fatal("Client.RedeemCoupon() error = nil, want error")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("logged %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+
+	tests := []struct {
+		line    string
+		want    []string
+		notWant []string
+	}{
+		{
+			line: lines[0],
+			want: []string{
+				"level=DEBUG", "api=trade", "method=WithdrawCoin", "params.amount=1",
+				"params.coinName=[REDACTED]", "params.address=[REDACTED]", "status=200", "latency=",
+			},
+			notWant: []string{"1SecretAddress", "btc", "error="},
+		},
+		{
+			line: lines[1],
+			want: []string{
+				"level=ERROR", "method=RedeemCoupon", "params.coupon=[REDACTED]", "status=200",
+				`error="server respond with error: coupon [REDACTED] not found, key [REDACTED]"`,
+			},
+			notWant: []string{"WEXUSD-SECRET-CODE", "my-api-key"},
+		},
+	}
+	for _, tt := range tests {
+		for _, want := range tt.want {
+			if !strings.Contains(tt.line, want) {
+				t.Errorf("log line %q doesn't contain %q", tt.line, want)
+			}
+		}
+		for _, notWant := range tt.notWant {
+			if strings.Contains(tt.line, notWant) {
+				t.Errorf("log line %q contains %q", tt.line, notWant)
+			}
+		}
+	}
+
+	if strings.Contains(buf.String(), "my-api-secret") {
+		t.Errorf("log contains secret:\n%s", buf.String())
+	}
+}
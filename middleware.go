@@ -0,0 +1,83 @@
+package wexapi
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// API is a kind of the wex api.
+type API int
+
+// Available apis.
+const (
+	PublicAPI API = iota
+	TradeAPI
+)
+
+func (api API) String() string {
+	if api == TradeAPI {
+		return "trade"
+	}
+	return "public"
+}
+
+// Param is a parameter of the api request.
+type Param struct {
+	Key, Value string
+
+	// Sensitive marks values which must not be
+	// exposed outside of the request, e.g. in logs.
+	Sensitive bool
+}
+
+// Request is an api request passed through the middlewares.
+// Method is a wex api method, e.g. "getInfo" or "depth", and
+// Pairs are pairs of the public api request, e.g. of "depth".
+// Nonce and signature of the trade requests are added
+// after middlewares, so they can't be seen or changed.
+type Request struct {
+	API    API
+	Method string
+	Pairs  []Pair
+	Params []Param
+}
+
+// Response is a successful api response passed through the
+// middlewares. Result is the whole body of the public api
+// response and the "return" field of the trade api one,
+// it's decoded after middlewares. Secrets of the result,
+// e.g. code of the created coupon, are redacted.
+type Response struct {
+	StatusCode int
+	Result     json.RawMessage
+}
+
+// Handler sends the request to the server. Errors returned
+// by the server are decoded into APIError.
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+// Middleware wraps handler to observe or change requests,
+// responses and errors, e.g. for logging or metrics.
+type Middleware func(next Handler) Handler
+
+// SetMiddleware adds middlewares wrapping every attempt of the
+// api requests, the first one is the outermost. Rate limits and
+// retries are applied outside of the middlewares, logging of
+// SetLogger inside of them.
+func SetMiddleware(middlewares ...Middleware) Option {
+	return func(cli *Client) {
+		cli.middlewares = append(cli.middlewares, middlewares...)
+	}
+}
+
+// handle sends req through the middlewares.
+func (cli *Client) handle(ctx context.Context, req *Request, send Handler) (*Response, error) {
+	handler := send
+	if cli.logger != nil {
+		handler = cli.logRequests(handler)
+	}
+	for i := len(cli.middlewares) - 1; i >= 0; i-- {
+		handler = cli.middlewares[i](handler)
+	}
+	return handler(ctx, req)
+}
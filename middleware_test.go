@@ -0,0 +1,108 @@
+package wexapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestClient_Middleware(t *testing.T) {
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/tapi" {
+			fmt.Fprint(w, `{"success":0,"error":"api key dont have trade permission"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"btc_usd":[{"type":"bid","price":1,"amount":1,"tid":%s,"timestamp":1}]}`, r.URL.Query().Get("limit"))
+	}))
+	defer server.Close()
+	httpClient := testingHTTPClient(server)
+
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) (*Response, error) {
+				resp, err := next(ctx, req)
+				calls = append(calls, fmt.Sprintf("%s %s %s %v %v %v", name, req.API, req.Method, req.Pairs, req.Params, err))
+				return resp, err
+			}
+		}
+	}
+	rewriteLimit := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			for i := range req.Params {
+				if req.Params[i].Key == "limit" {
+					req.Params[i].Value = "42"
+				}
+			}
+			return next(ctx, req)
+		}
+	}
+
+	cli := NewClient("key", "secret",
+		SetHTTPClient(httpClient),
+		SetMiddleware(record("outer"), record("inner")),
+		SetMiddleware(rewriteLimit),
+	)
+
+	trades, err := cli.Trades(NewPair("btc", "usd"), 1)
+	if err != nil {
+		t.Fatalf("Client.Trades() error = %v", err)
+	}
+	if len(trades) != 1 || trades[0].ID != 42 {
+		t.Errorf("Client.Trades() = %v, want trade with rewritten limit as ID", trades)
+	}
+
+	_, err = cli.GetInfo()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != ErrNoPermission {
+		t.Errorf("Client.GetInfo() error = %v, want APIError with %v", err, ErrNoPermission)
+	}
+
+	want := []string{
+		"inner public trades [btc_usd] [{limit 42 false}] <nil>",
+		"outer public trades [btc_usd] [{limit 42 false}] <nil>",
+		"inner trade getInfo [] [] server respond with error: api key dont have trade permission",
+		"outer trade getInfo [] [] server respond with error: api key dont have trade permission",
+	}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Errorf("middleware calls = %q, want %q", calls, want)
+	}
+}
+
+func TestClient_MiddlewareRedactsResult(t *testing.T) {
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, createCouponResponse)
+	}))
+	defer server.Close()
+	httpClient := testingHTTPClient(server)
+
+	var results []string
+	record := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			resp, err := next(ctx, req)
+			if resp != nil {
+				results = append(results, string(resp.Result))
+			}
+			return resp, err
+		}
+	}
+
+	cli := NewClient("key", "secret", SetHTTPClient(httpClient), SetMiddleware(record))
+	coupon, err := cli.CreateCoupon("USD", decimal.New(1, 0), "")
+	if err != nil {
+		t.Fatalf("Client.CreateCoupon() error = %v", err)
+	}
+
+	code := "WEXUSD-0YT3HTDF-M0Y4DL1U-AS08CDYZ-TI2L54DH"
+	if string(coupon.Code) != code {
+		t.Errorf("Client.CreateCoupon() code = %s, want %s", string(coupon.Code), code)
+	}
+	if len(results) != 1 || strings.Contains(results[0], code) || !strings.Contains(results[0], `"coupon":"[REDACTED]"`) {
+		t.Errorf("middleware got results %v, want redacted coupon", results)
+	}
+}
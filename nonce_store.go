@@ -0,0 +1,174 @@
+package wexapi
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const maxNonce = uint32(math.MaxUint32) - 1
+
+// ErrFileLockUnsupported is returned by NewFileNonceStore on
+// platforms where files can't be locked across processes.
+var ErrFileLockUnsupported = errors.New("file locking isn't supported on this platform")
+
+// NonceStore reserves nonces for the trade api requests.
+// Implementations must be safe for concurrent use, every
+// nonce returned by Next must be used only once and be
+// greater than all previously returned ones.
+type NonceStore interface {
+	// Next reserves and returns the next nonce.
+	// It returns ErrNonceOverflow when max value
+	// for the key is reached.
+	Next() (uint32, error)
+	// Sync moves the store forward, so the nonce returned
+	// by the following Next call is at least next.
+	Sync(next uint32) error
+}
+
+// MemoryNonceStore keeps nonce in memory of the process.
+// It is lock-free, so it's cheap to share between
+// goroutines placing orders concurrently.
+// Use NewMemoryNonceStore to initialize one.
+type MemoryNonceStore struct {
+	nonce uint32
+}
+
+// NewMemoryNonceStore returns store which starts from the nonce.
+func NewMemoryNonceStore(nonce uint32) *MemoryNonceStore {
+	return &MemoryNonceStore{
+		nonce: nonce,
+	}
+}
+
+// Next implements NonceStore.
+func (s *MemoryNonceStore) Next() (uint32, error) {
+	for {
+		nonce := atomic.LoadUint32(&s.nonce)
+		if nonce >= maxNonce {
+			return 0, ErrNonceOverflow
+		}
+
+		if atomic.CompareAndSwapUint32(&s.nonce, nonce, nonce+1) {
+			return nonce, nil
+		}
+	}
+}
+
+// Sync implements NonceStore.
+func (s *MemoryNonceStore) Sync(next uint32) error {
+	for {
+		nonce := atomic.LoadUint32(&s.nonce)
+		if nonce >= next {
+			return nil
+		}
+
+		if atomic.CompareAndSwapUint32(&s.nonce, nonce, next) {
+			return nil
+		}
+	}
+}
+
+// FileNonceStore keeps nonce in the file, so it survives
+// restarts and can be shared by several processes using
+// the same key. The file is locked during every operation,
+// which is supported on unix systems and windows only.
+// Use NewFileNonceStore to initialize one.
+type FileNonceStore struct {
+	path string
+}
+
+// NewFileNonceStore returns store which keeps nonce in
+// the file at path. The file is created if it doesn't
+// exist and is seeded with current unix time. It fails with
+// ErrFileLockUnsupported where the file can't be locked.
+func NewFileNonceStore(path string) (*FileNonceStore, error) {
+	s := FileNonceStore{
+		path: path,
+	}
+
+	// Makes sure the file is accessible and seeded.
+	err := s.update(func(nonce uint32) (uint32, error) {
+		return nonce, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// Next implements NonceStore.
+func (s *FileNonceStore) Next() (uint32, error) {
+	var reserved uint32
+	err := s.update(func(nonce uint32) (uint32, error) {
+		if nonce >= maxNonce {
+			return 0, ErrNonceOverflow
+		}
+		reserved = nonce
+		return nonce + 1, nil
+	})
+	return reserved, err
+}
+
+// Sync implements NonceStore.
+func (s *FileNonceStore) Sync(next uint32) error {
+	return s.update(func(nonce uint32) (uint32, error) {
+		if nonce < next {
+			return next, nil
+		}
+		return nonce, nil
+	})
+}
+
+// update reads nonce from the locked file and writes back
+// the value returned by fn.
+func (s *FileNonceStore) update(fn func(nonce uint32) (uint32, error)) error {
+	file, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Wrap(err, "open nonce file")
+	}
+	defer file.Close()
+
+	if err := lockFile(file); err != nil {
+		return errors.Wrap(err, "lock nonce file")
+	}
+	defer unlockFile(file)
+
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return errors.Wrap(err, "read nonce file")
+	}
+
+	nonce := uint32(time.Now().Unix())
+	if content := strings.TrimSpace(string(data)); content != "" {
+		parsed, err := strconv.ParseUint(content, 10, 32)
+		if err != nil {
+			return errors.Wrap(err, "parse nonce file")
+		}
+		nonce = uint32(parsed)
+	}
+
+	nonce, err = fn(nonce)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Seek(0, 0); err != nil {
+		return errors.Wrap(err, "seek nonce file")
+	}
+	if err := file.Truncate(0); err != nil {
+		return errors.Wrap(err, "truncate nonce file")
+	}
+	if _, err := file.WriteString(strconv.FormatUint(uint64(nonce), 10)); err != nil {
+		return errors.Wrap(err, "write nonce file")
+	}
+
+	return errors.Wrap(file.Sync(), "sync nonce file")
+}
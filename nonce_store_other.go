@@ -0,0 +1,19 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows
+
+package wexapi
+
+import (
+	"os"
+)
+
+// Locking files across processes isn't supported
+// on this platform, so FileNonceStore can't be used.
+
+func lockFile(file *os.File) error {
+	return ErrFileLockUnsupported
+}
+
+func unlockFile(file *os.File) error {
+	return ErrFileLockUnsupported
+}
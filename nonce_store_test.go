@@ -0,0 +1,160 @@
+package wexapi
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func testNonceStore(t *testing.T, store NonceStore) {
+	first, err := store.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+
+	second, err := store.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if second != first+1 {
+		t.Errorf("Next() = %d, want %d", second, first+1)
+	}
+
+	if err := store.Sync(first); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	got, err := store.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if got != second+1 {
+		t.Errorf("Next() after sync to lower nonce = %d, want %d", got, second+1)
+	}
+
+	if err := store.Sync(first + 100); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	got, err = store.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if got != first+100 {
+		t.Errorf("Next() after sync = %d, want %d", got, first+100)
+	}
+
+	if err := store.Sync(maxNonce); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if _, err := store.Next(); err != ErrNonceOverflow {
+		t.Errorf("Next() error = %v, want %v", err, ErrNonceOverflow)
+	}
+}
+
+func testNonceStoreConcurrent(t *testing.T, store NonceStore) {
+	const workers, calls = 8, 25
+
+	var mu sync.Mutex
+	seen := make(map[uint32]bool)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < calls; j++ {
+				nonce, err := store.Next()
+				if err != nil {
+					t.Errorf("Next() error = %v", err)
+					return
+				}
+				mu.Lock()
+				if seen[nonce] {
+					t.Errorf("Next() returned %d twice", nonce)
+				}
+				seen[nonce] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != workers*calls {
+		t.Errorf("got %d unique nonces, want %d", len(seen), workers*calls)
+	}
+}
+
+func TestMemoryNonceStore(t *testing.T) {
+	testNonceStore(t, NewMemoryNonceStore(1))
+	testNonceStoreConcurrent(t, NewMemoryNonceStore(1))
+}
+
+func TestFileNonceStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wexapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := NewFileNonceStore(filepath.Join(dir, "nonce"))
+	if errors.Is(err, ErrFileLockUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("NewFileNonceStore() error = %v", err)
+	}
+	testNonceStore(t, store)
+
+	path := filepath.Join(dir, "shared")
+	if err := ioutil.WriteFile(path, []byte("100"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := NewFileNonceStore(path)
+	if err != nil {
+		t.Fatalf("NewFileNonceStore() error = %v", err)
+	}
+	second, err := NewFileNonceStore(path)
+	if err != nil {
+		t.Fatalf("NewFileNonceStore() error = %v", err)
+	}
+
+	if got, _ := first.Next(); got != 100 {
+		t.Errorf("Next() = %d, want 100", got)
+	}
+	if got, _ := second.Next(); got != 101 {
+		t.Errorf("Next() from second store = %d, want 101", got)
+	}
+
+	testNonceStoreConcurrent(t, second)
+}
+
+func TestFileNonceStore_invalidContent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wexapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "nonce")
+	if err := ioutil.WriteFile(path, []byte("nonce"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewFileNonceStore(path); err == nil {
+		t.Error("NewFileNonceStore() error = nil, want parse error")
+	}
+}
+
+func BenchmarkMemoryNonceStore_Next(b *testing.B) {
+	store := NewMemoryNonceStore(1)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			store.Next()
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package wexapi
+
+import (
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+var two = decimal.New(2, 0)
+
+// ErrNotEnoughLiquidity caused when order book doesn't
+// have enough orders to fill the requested amount.
+var ErrNotEnoughLiquidity = errors.New("not enough liquidity")
+
+// BestAsk returns the lowest ask.
+func (book OrderBook) BestAsk() (Order, bool) {
+	if len(book.Asks) == 0 {
+		return Order{}, false
+	}
+	return book.Asks[0], true
+}
+
+// BestBid returns the highest bid.
+func (book OrderBook) BestBid() (Order, bool) {
+	if len(book.Bids) == 0 {
+		return Order{}, false
+	}
+	return book.Bids[0], true
+}
+
+// Spread returns difference between the best ask
+// and the best bid rates.
+func (book OrderBook) Spread() (decimal.Decimal, bool) {
+	ask, bid, ok := book.best()
+	if !ok {
+		return decimal.Zero, false
+	}
+	return ask.Rate.Sub(bid.Rate), true
+}
+
+// MidPrice returns average of the best ask
+// and the best bid rates.
+func (book OrderBook) MidPrice() (decimal.Decimal, bool) {
+	ask, bid, ok := book.best()
+	if !ok {
+		return decimal.Zero, false
+	}
+	return ask.Rate.Add(bid.Rate).Div(two), true
+}
+
+func (book OrderBook) best() (Order, Order, bool) {
+	ask, askOk := book.BestAsk()
+	bid, bidOk := book.BestBid()
+	return ask, bid, askOk && bidOk
+}
+
+// CumulativeAsks returns asks with Amount and Total
+// accumulated from the best ask.
+func (book OrderBook) CumulativeAsks() []Order {
+	return cumulative(book.Asks)
+}
+
+// CumulativeBids returns bids with Amount and Total
+// accumulated from the best bid.
+func (book OrderBook) CumulativeBids() []Order {
+	return cumulative(book.Bids)
+}
+
+func cumulative(orders []Order) []Order {
+	result := make([]Order, len(orders))
+
+	amount, total := decimal.Zero, decimal.Zero
+	for i, order := range orders {
+		amount = amount.Add(order.Amount)
+		total = total.Add(order.Rate.Mul(order.Amount))
+		result[i] = Order{
+			Rate:   order.Rate,
+			Amount: amount,
+			Total:  total,
+		}
+	}
+
+	return result
+}
+
+// VWAP returns volume-weighted average price of the
+// market order of the amount, buy orders are filled by
+// asks and sell orders by bids.
+func (book OrderBook) VWAP(side Side, amount decimal.Decimal) (decimal.Decimal, error) {
+	orders, err := book.takenBy(side)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if !amount.IsPositive() {
+		return decimal.Zero, errors.Errorf("invalid amount %s", amount)
+	}
+
+	remains, total := amount, decimal.Zero
+	for _, order := range orders {
+		filled := decimal.Min(remains, order.Amount)
+		total = total.Add(order.Rate.Mul(filled))
+		remains = remains.Sub(filled)
+
+		if remains.IsZero() {
+			return total.Div(amount), nil
+		}
+	}
+
+	return decimal.Zero, ErrNotEnoughLiquidity
+}
+
+// PriceImpact returns relative difference between VWAP
+// of the market order of the amount and the best rate,
+// e.g. 0.01 means the order moves price by 1%. It is
+// positive for both sides. It fails if the best rate
+// isn't positive.
+func (book OrderBook) PriceImpact(side Side, amount decimal.Decimal) (decimal.Decimal, error) {
+	vwap, err := book.VWAP(side, amount)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	orders, _ := book.takenBy(side)
+	best := orders[0].Rate
+	if !best.IsPositive() {
+		return decimal.Zero, errors.Errorf("invalid best rate %s", best)
+	}
+	return vwap.Sub(best).Abs().Div(best), nil
+}
+
+// takenBy returns orders filling market order of the side.
+func (book OrderBook) takenBy(side Side) ([]Order, error) {
+	if err := side.Validate(); err != nil {
+		return nil, err
+	}
+
+	orders := book.Asks
+	if side == Sell {
+		orders = book.Bids
+	}
+	if len(orders) == 0 {
+		return nil, ErrNotEnoughLiquidity
+	}
+
+	return orders, nil
+}
@@ -0,0 +1,192 @@
+package wexapi
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func newTestOrder(rate, amount string) Order {
+	order := Order{
+		Rate:   decimal.RequireFromString(rate),
+		Amount: decimal.RequireFromString(amount),
+	}
+	order.CalculateTotal()
+	return order
+}
+
+var testOrderBook = OrderBook{
+	Asks: []Order{
+		newTestOrder("101", "1"),
+		newTestOrder("102", "2"),
+		newTestOrder("105", "3"),
+	},
+	Bids: []Order{
+		newTestOrder("99", "2"),
+		newTestOrder("98", "1"),
+		newTestOrder("90", "5"),
+	},
+}
+
+func TestOrderBook_SpreadAndMidPrice(t *testing.T) {
+	tests := []struct {
+		name       string
+		book       OrderBook
+		wantSpread string
+		wantMid    string
+		wantOk     bool
+	}{
+		{
+			name:       "full book",
+			book:       testOrderBook,
+			wantSpread: "2",
+			wantMid:    "100",
+			wantOk:     true,
+		},
+		{
+			name:       "no bids",
+			book:       OrderBook{Asks: testOrderBook.Asks},
+			wantSpread: "0",
+			wantMid:    "0",
+			wantOk:     false,
+		},
+		{
+			name:       "empty",
+			book:       OrderBook{},
+			wantSpread: "0",
+			wantMid:    "0",
+			wantOk:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spread, ok := tt.book.Spread()
+			if ok != tt.wantOk || !spread.Equal(decimal.RequireFromString(tt.wantSpread)) {
+				t.Errorf("OrderBook.Spread() = %s, %v, want %s, %v", spread, ok, tt.wantSpread, tt.wantOk)
+			}
+			mid, ok := tt.book.MidPrice()
+			if ok != tt.wantOk || !mid.Equal(decimal.RequireFromString(tt.wantMid)) {
+				t.Errorf("OrderBook.MidPrice() = %s, %v, want %s, %v", mid, ok, tt.wantMid, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestOrderBook_Best(t *testing.T) {
+	if ask, ok := testOrderBook.BestAsk(); !ok || !ask.Rate.Equal(decimal.New(101, 0)) {
+		t.Errorf("OrderBook.BestAsk() = %v, %v, want 101", ask, ok)
+	}
+	if bid, ok := testOrderBook.BestBid(); !ok || !bid.Rate.Equal(decimal.New(99, 0)) {
+		t.Errorf("OrderBook.BestBid() = %v, %v, want 99", bid, ok)
+	}
+	if _, ok := (OrderBook{}).BestAsk(); ok {
+		t.Error("OrderBook.BestAsk() of empty book ok = true")
+	}
+}
+
+func TestOrderBook_Cumulative(t *testing.T) {
+	tests := []struct {
+		name    string
+		got     []Order
+		amounts []string
+		totals  []string
+	}{
+		{
+			name:    "asks",
+			got:     testOrderBook.CumulativeAsks(),
+			amounts: []string{"1", "3", "6"},
+			totals:  []string{"101", "305", "620"},
+		},
+		{
+			name:    "bids",
+			got:     testOrderBook.CumulativeBids(),
+			amounts: []string{"2", "3", "8"},
+			totals:  []string{"198", "296", "746"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.got) != len(tt.amounts) {
+				t.Fatalf("got %d levels, want %d", len(tt.got), len(tt.amounts))
+			}
+			for i, order := range tt.got {
+				if !order.Amount.Equal(decimal.RequireFromString(tt.amounts[i])) || !order.Total.Equal(decimal.RequireFromString(tt.totals[i])) {
+					t.Errorf("level %d = %s/%s, want %s/%s", i, order.Amount, order.Total, tt.amounts[i], tt.totals[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOrderBook_VWAPAndPriceImpact(t *testing.T) {
+	tests := []struct {
+		name       string
+		side       Side
+		amount     string
+		wantVWAP   string
+		wantImpact string
+		wantErr    error
+	}{
+		{
+			name:       "buy within best ask",
+			side:       Buy,
+			amount:     "0.5",
+			wantVWAP:   "101",
+			wantImpact: "0",
+		},
+		{
+			name:       "buy through levels",
+			side:       Buy,
+			amount:     "4",
+			wantVWAP:   "102.5",
+			wantImpact: "0.0148514851485149",
+		},
+		{
+			name:       "sell through levels",
+			side:       Sell,
+			amount:     "4",
+			wantVWAP:   "96.5",
+			wantImpact: "0.0252525252525253",
+		},
+		{
+			name:    "not enough liquidity",
+			side:    Sell,
+			amount:  "9",
+			wantErr: ErrNotEnoughLiquidity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount := decimal.RequireFromString(tt.amount)
+
+			vwap, err := testOrderBook.VWAP(tt.side, amount)
+			if err != tt.wantErr {
+				t.Fatalf("OrderBook.VWAP() error = %v, want %v", err, tt.wantErr)
+			}
+			impact, err := testOrderBook.PriceImpact(tt.side, amount)
+			if err != tt.wantErr {
+				t.Fatalf("OrderBook.PriceImpact() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if !vwap.Equal(decimal.RequireFromString(tt.wantVWAP)) {
+				t.Errorf("OrderBook.VWAP() = %s, want %s", vwap, tt.wantVWAP)
+			}
+			if !impact.Equal(decimal.RequireFromString(tt.wantImpact)) {
+				t.Errorf("OrderBook.PriceImpact() = %s, want %s", impact, tt.wantImpact)
+			}
+		})
+	}
+}
+
+func TestOrderBook_PriceImpactZeroRate(t *testing.T) {
+	book := OrderBook{
+		Asks: []Order{newTestOrder("0", "1"), newTestOrder("101", "1")},
+	}
+
+	if _, err := book.PriceImpact(Buy, decimal.New(2, 0)); err == nil {
+		t.Error("OrderBook.PriceImpact() error = nil, want error")
+	}
+}
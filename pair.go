@@ -0,0 +1,133 @@
+package wexapi
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const pairSeparator = "_"
+
+// Side is a side of the order.
+type Side string
+
+// Available order sides.
+const (
+	Buy  Side = "buy"
+	Sell Side = "sell"
+)
+
+// ParseSide parses "buy" or "sell" into Side.
+func ParseSide(s string) (Side, error) {
+	side := Side(s)
+	if err := side.Validate(); err != nil {
+		return "", err
+	}
+	return side, nil
+}
+
+// Validate returns error if side is neither Buy nor Sell.
+func (side Side) Validate() error {
+	if side != Buy && side != Sell {
+		return errors.Errorf("invalid side %q", string(side))
+	}
+	return nil
+}
+
+func (side Side) String() string {
+	return string(side)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, it also
+// accepts "bid" and "ask" used by the public trades.
+func (side *Side) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "bid":
+		*side = Buy
+		return nil
+	case "ask":
+		*side = Sell
+		return nil
+	}
+
+	parsed, err := ParseSide(string(text))
+	if err != nil {
+		return err
+	}
+	*side = parsed
+	return nil
+}
+
+// Pair is a currency pair, e.g. btc_usd where
+// btc is the base and usd is the quote currency.
+type Pair struct {
+	Base, Quote string
+}
+
+// NewPair returns pair of the base and quote currencies.
+func NewPair(base, quote string) Pair {
+	return Pair{
+		Base:  base,
+		Quote: quote,
+	}
+}
+
+// ParsePair parses "base_quote" format into Pair.
+func ParsePair(s string) (Pair, error) {
+	parts := strings.Split(s, pairSeparator)
+	if len(parts) != 2 {
+		return Pair{}, errors.Errorf("invalid pair %q", s)
+	}
+
+	pair := NewPair(parts[0], parts[1])
+	if err := pair.Validate(); err != nil {
+		return Pair{}, err
+	}
+	return pair, nil
+}
+
+// Validate returns error if any of the currencies is empty
+// or contains anything except lower case letters and digits.
+func (pair Pair) Validate() error {
+	if !validCurrency(pair.Base) || !validCurrency(pair.Quote) {
+		return errors.Errorf("invalid pair %q", pair.String())
+	}
+	return nil
+}
+
+// IsZero reports whether pair is empty.
+func (pair Pair) IsZero() bool {
+	return pair == Pair{}
+}
+
+func (pair Pair) String() string {
+	return pair.Base + pairSeparator + pair.Quote
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (pair Pair) MarshalText() ([]byte, error) {
+	return []byte(pair.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (pair *Pair) UnmarshalText(text []byte) error {
+	parsed, err := ParsePair(string(text))
+	if err != nil {
+		return err
+	}
+	*pair = parsed
+	return nil
+}
+
+func validCurrency(currency string) bool {
+	if currency == "" {
+		return false
+	}
+
+	for _, r := range currency {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package wexapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestParsePair(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Pair
+		wantErr bool
+	}{
+		{
+			name:  "valid",
+			input: "btc_usd",
+			want:  NewPair("btc", "usd"),
+		},
+		{
+			name:  "digits",
+			input: "dsh1_btc",
+			want:  NewPair("dsh1", "btc"),
+		},
+		{
+			name:    "no separator",
+			input:   "btcusd",
+			wantErr: true,
+		},
+		{
+			name:    "upper case",
+			input:   "BTC_usd",
+			wantErr: true,
+		},
+		{
+			name:    "empty quote",
+			input:   "btc_",
+			wantErr: true,
+		},
+		{
+			name:    "too many parts",
+			input:   "btc_usd_eur",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePair(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePair() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePair() = %v, want %v", got, tt.want)
+			}
+			if !tt.wantErr && got.String() != tt.input {
+				t.Errorf("Pair.String() = %s, want %s", got, tt.input)
+			}
+		})
+	}
+}
+
+func TestSide_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Side
+		wantErr bool
+	}{
+		{name: "buy", input: `"buy"`, want: Buy},
+		{name: "sell", input: `"sell"`, want: Sell},
+		{name: "bid", input: `"bid"`, want: Buy},
+		{name: "ask", input: `"ask"`, want: Sell},
+		{name: "capitalized", input: `"Buy"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Side
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Side.UnmarshalText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Side.UnmarshalText() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_TradeValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		pair Pair
+		side Side
+	}{
+		{
+			name: "invalid pair",
+			pair: NewPair("btcusd", ""),
+			side: Buy,
+		},
+		{
+			name: "invalid side",
+			pair: NewPair("btc", "usd"),
+			side: Side("Buy"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			}))
+			defer server.Close()
+			httpClient := testingHTTPClient(server)
+
+			cli := NewClient("", "", SetHTTPClient(httpClient))
+			if _, err := cli.Trade(tt.pair, tt.side, decimal.Zero, decimal.Zero); err == nil {
+				t.Error("Client.Trade() error = nil, want validation error")
+			}
+			if called {
+				t.Error("Client.Trade() sent invalid request to the server")
+			}
+		})
+	}
+}
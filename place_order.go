@@ -0,0 +1,211 @@
+package wexapi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// reconcileTimeout limits requests looking for
+	// the order, ctx of the placement could be done.
+	reconcileTimeout = defaultTimeout
+	// reconcileClockSkew is allowed difference
+	// between the server and the local clocks.
+	reconcileClockSkew = 5 * time.Second
+)
+
+// ErrOrderNotPlaced means order is known not to be placed,
+// so it's safe to place it again.
+var ErrOrderNotPlaced = errors.New("order not placed")
+
+// ErrAmbiguousOrder means several orders match the
+// placed one, so it's unknown which of them it is.
+var ErrAmbiguousOrder = errors.New("several orders match")
+
+// OrderNotPlacedError is returned by PlaceOrder if order
+// is known not to be placed, Err is the cause.
+type OrderNotPlacedError struct {
+	Err error
+}
+
+func (e *OrderNotPlacedError) Error() string {
+	return fmt.Sprintf("order not placed: %v", e.Err)
+}
+
+// Unwrap returns the cause.
+func (e *OrderNotPlacedError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrOrderNotPlaced.
+func (e *OrderNotPlacedError) Is(target error) bool {
+	return target == ErrOrderNotPlaced
+}
+
+// PlaceOrder is like Trade, but if it's unknown whether order was
+// placed, e.g. on timeout after request was sent, it looks for the
+// order of the same pair, side, rate and amount created since the
+// call in the active orders and trade history. Orders with IDs not
+// above the largest one seen by the client before the call are
+// skipped. Found order is returned as UserTrade without funds.
+// Error matching ErrOrderNotPlaced is returned if order wasn't
+// placed, ErrAmbiguousOrder if several orders match, any other
+// error means the result is still unknown.
+//
+// Order can't be placed after it's looked up, because
+// server rejects requests with nonces lower than the
+// nonces of the lookup requests.
+func (cli *Client) PlaceOrder(pair Pair, side Side, rate, amount decimal.Decimal) (UserTrade, error) {
+	return cli.PlaceOrderContext(context.Background(), pair, side, rate, amount)
+}
+
+// PlaceOrderContext is like PlaceOrder but uses ctx for the request.
+func (cli *Client) PlaceOrderContext(ctx context.Context, pair Pair, side Side, rate, amount decimal.Decimal) (UserTrade, error) {
+	if err := pair.Validate(); err != nil {
+		return UserTrade{}, &OrderNotPlacedError{Err: err}
+	}
+	if err := side.Validate(); err != nil {
+		return UserTrade{}, &OrderNotPlacedError{Err: err}
+	}
+
+	// Rate and amount are rounded before placing,
+	// so order is looked up by the sent ones.
+	if cli.orderValidator != nil {
+		var err error
+		rate, amount, err = cli.orderValidator.validate(ctx, pair, side, rate, amount)
+		if err != nil {
+			return UserTrade{}, &OrderNotPlacedError{Err: err}
+		}
+	}
+
+	// Orders seen before the call can't be the placed one.
+	lastSeen := cli.lastOrderID.Load()
+	started := time.Now()
+	userTrade, err := cli.trade(ctx, pair, side, rate, amount)
+	if err == nil {
+		return userTrade, nil
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) || isPreSend(err) {
+		return UserTrade{}, &OrderNotPlacedError{Err: err}
+	}
+
+	reconcileCtx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	since := started.Add(-reconcileClockSkew)
+	userTrade, found, reconcileErr := cli.findOrder(reconcileCtx, pair, side, rate, amount, since, lastSeen)
+	if reconcileErr != nil {
+		return UserTrade{}, errors.Wrapf(reconcileErr, "find order after error: %v", err)
+	}
+	if !found {
+		return UserTrade{}, &OrderNotPlacedError{Err: err}
+	}
+
+	return userTrade, nil
+}
+
+// findOrder looks for the order with ID above lastSeen in active
+// orders and orders filled completely in the trade history.
+func (cli *Client) findOrder(ctx context.Context, pair Pair, side Side, rate, amount decimal.Decimal, since time.Time, lastSeen uint64) (UserTrade, bool, error) {
+	orders, err := cli.ActiveOrdersContext(ctx, pair)
+	if err != nil && !isNoResults(err) {
+		return UserTrade{}, false, errors.Wrap(err, "active orders")
+	}
+
+	var candidates []UserTrade
+	active := make(map[uint64]bool, len(orders))
+	for _, order := range orders {
+		active[order.ID] = true
+
+		if order.ID <= lastSeen || order.Type != side || !order.Rate.Equal(rate) ||
+			!order.StartAmount.Equal(amount) || time.Time(order.TimestampCreated).Before(since) {
+			continue
+		}
+
+		candidates = append(candidates, UserTrade{
+			OrderID:  order.ID,
+			Received: order.StartAmount.Sub(order.Amount),
+			Remains:  order.Amount,
+		})
+	}
+
+	trades, err := cli.TradeHistoryContext(ctx, TradeHistoryFilter{
+		HistoryFilter: HistoryFilter{Since: since, Order: SortAsc},
+		Pair:          pair,
+	})
+	if err != nil && !isNoResults(err) {
+		return UserTrade{}, false, errors.Wrap(err, "trade history")
+	}
+
+	// Filled order has trades at its rate or better.
+	filled := make(map[uint64]decimal.Decimal)
+	var orderIDs []uint64
+	for _, trade := range trades {
+		if trade.OrderID <= lastSeen || active[trade.OrderID] || trade.Type != side ||
+			(side == Buy && trade.Rate.GreaterThan(rate)) ||
+			(side == Sell && trade.Rate.LessThan(rate)) {
+			continue
+		}
+
+		if _, ok := filled[trade.OrderID]; !ok {
+			orderIDs = append(orderIDs, trade.OrderID)
+		}
+		filled[trade.OrderID] = filled[trade.OrderID].Add(trade.Amount)
+	}
+
+	for _, id := range orderIDs {
+		if filled[id].Equal(amount) {
+			candidates = append(candidates, UserTrade{
+				OrderID:  id,
+				Received: amount,
+				Remains:  decimal.Zero,
+			})
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return UserTrade{}, false, nil
+	case 1:
+		return candidates[0], true, nil
+	}
+
+	ids := make([]uint64, len(candidates))
+	for i, candidate := range candidates {
+		ids[i] = candidate.OrderID
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	return UserTrade{}, false, errors.Wrapf(ErrAmbiguousOrder, "orders %v", ids)
+}
+
+// seeOrder moves the largest order ID seen by the client forward.
+func (cli *Client) seeOrder(id uint64) {
+	for {
+		last := cli.lastOrderID.Load()
+		if id <= last || cli.lastOrderID.CompareAndSwap(last, id) {
+			return
+		}
+	}
+}
+
+// isNoResults reports whether err is returned by
+// the server instead of the empty list.
+func isNoResults(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	message := strings.ToLower(apiErr.Message)
+	return strings.HasPrefix(message, "no orders") || strings.HasPrefix(message, "no trades")
+}
@@ -0,0 +1,189 @@
+package wexapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestClient_PlaceOrder(t *testing.T) {
+	now := time.Now().Unix()
+	noOrders := `{"success":0,"error":"no orders"}`
+	noTrades := `{"success":0,"error":"no trades"}`
+
+	tests := []struct {
+		name      string
+		seen      uint64
+		responses map[string]string
+		want      UserTrade
+		notPlaced bool
+		ambiguous bool
+		wantErr   bool
+	}{
+		{
+			name: "placed",
+			responses: map[string]string{
+				"Trade": `{"success":1,"return":{"received":0,"remains":1,"order_id":7}}`,
+			},
+			want: UserTrade{OrderID: 7, Received: decimal.Zero, Remains: decimal.New(1, 0)},
+		},
+		{
+			name: "rejected",
+			responses: map[string]string{
+				"Trade": `{"success":0,"error":"It is not enough USD for purchase"}`,
+			},
+			notPlaced: true,
+			wantErr:   true,
+		},
+		{
+			name: "found in active orders",
+			responses: map[string]string{
+				"ActiveOrders": fmt.Sprintf(`{"success":1,"return":{
+					"5":{"pair":"btc_usd","type":"buy","start_amount":1,"amount":1,"rate":100,"timestamp_created":%d},
+					"8":{"pair":"btc_usd","type":"buy","start_amount":1,"amount":0.25,"rate":100,"timestamp_created":%d},
+					"9":{"pair":"btc_usd","type":"sell","start_amount":1,"amount":1,"rate":100,"timestamp_created":%d}
+				}}`, now-3600, now, now),
+				"TradeHistory": noTrades,
+			},
+			want: UserTrade{OrderID: 8, Received: decimal.RequireFromString("0.75"), Remains: decimal.RequireFromString("0.25")},
+		},
+		{
+			name: "several matching orders",
+			responses: map[string]string{
+				"ActiveOrders": fmt.Sprintf(`{"success":1,"return":{
+					"8":{"pair":"btc_usd","type":"buy","start_amount":1,"amount":1,"rate":100,"timestamp_created":%d},
+					"10":{"pair":"btc_usd","type":"buy","start_amount":1,"amount":1,"rate":100,"timestamp_created":%d}
+				}}`, now, now),
+				"TradeHistory": noTrades,
+			},
+			ambiguous: true,
+			wantErr:   true,
+		},
+		{
+			name: "orders seen before call skipped",
+			seen: 8,
+			responses: map[string]string{
+				"ActiveOrders": fmt.Sprintf(`{"success":1,"return":{
+					"8":{"pair":"btc_usd","type":"buy","start_amount":1,"amount":1,"rate":100,"timestamp_created":%d},
+					"10":{"pair":"btc_usd","type":"buy","start_amount":1,"amount":1,"rate":100,"timestamp_created":%d}
+				}}`, now, now),
+				"TradeHistory": noTrades,
+			},
+			want: UserTrade{OrderID: 10, Received: decimal.Zero, Remains: decimal.New(1, 0)},
+		},
+		{
+			name: "found in trade history",
+			responses: map[string]string{
+				"ActiveOrders": noOrders,
+				"TradeHistory": fmt.Sprintf(`{"success":1,"return":{
+					"1":{"pair":"btc_usd","type":"buy","amount":0.5,"rate":99,"order_id":11,"timestamp":%d},
+					"2":{"pair":"btc_usd","type":"buy","amount":0.5,"rate":100,"order_id":11,"timestamp":%d},
+					"3":{"pair":"btc_usd","type":"buy","amount":1,"rate":101,"order_id":12,"timestamp":%d}
+				}}`, now, now, now),
+			},
+			want: UserTrade{OrderID: 11, Received: decimal.New(1, 0), Remains: decimal.Zero},
+		},
+		{
+			name: "not found",
+			responses: map[string]string{
+				"ActiveOrders": noOrders,
+				"TradeHistory": noTrades,
+			},
+			notPlaced: true,
+			wantErr:   true,
+		},
+		{
+			name: "lookup failed",
+			responses: map[string]string{
+				"ActiveOrders": `{"success":0,"error":"api key dont have info permission"}`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response, ok := tt.responses[r.FormValue("method")]
+				if !ok {
+					w.WriteHeader(http.StatusGatewayTimeout)
+					return
+				}
+				fmt.Fprint(w, response)
+			}))
+			defer server.Close()
+			httpClient := testingHTTPClient(server)
+
+			cli := NewClient("key", "secret", SetHTTPClient(httpClient))
+			cli.seeOrder(tt.seen)
+			got, err := cli.PlaceOrder(NewPair("btc", "usd"), Buy, decimal.New(100, 0), decimal.New(1, 0))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Client.PlaceOrder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if notPlaced := errors.Is(err, ErrOrderNotPlaced); notPlaced != tt.notPlaced {
+				t.Errorf("Client.PlaceOrder() error = %v, not placed %v, want %v", err, notPlaced, tt.notPlaced)
+			}
+			if ambiguous := errors.Is(err, ErrAmbiguousOrder); ambiguous != tt.ambiguous {
+				t.Errorf("Client.PlaceOrder() error = %v, ambiguous %v, want %v", err, ambiguous, tt.ambiguous)
+			}
+			if !compareAsStrings(got, tt.want) {
+				t.Errorf("Client.PlaceOrder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type failingNonceStore struct{}
+
+func (failingNonceStore) Next() (uint32, error) { return 0, ErrNonceOverflow }
+func (failingNonceStore) Sync(uint32) error     { return nil }
+
+func TestClient_PlaceOrderNotSent(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		options []Option
+	}{
+		{
+			name:    "nonce overflow",
+			ctx:     context.Background(),
+			options: []Option{SetNonceStore(failingNonceStore{})},
+		},
+		{
+			name:    "canceled waiting for rate limit",
+			ctx:     canceled,
+			options: []Option{SetTradeRateLimit(1, time.Hour, 1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				fmt.Fprint(w, `{"success":0,"error":"no orders"}`)
+			}))
+			defer server.Close()
+			httpClient := testingHTTPClient(server)
+
+			cli := NewClient("key", "secret", append(tt.options, SetHTTPClient(httpClient))...)
+			// Takes the only token of the rate limiter.
+			cli.tradeLimiter.wait(context.Background())
+
+			_, err := cli.PlaceOrderContext(tt.ctx, NewPair("btc", "usd"), Buy, decimal.New(100, 0), decimal.New(1, 0))
+			if !errors.Is(err, ErrOrderNotPlaced) {
+				t.Errorf("Client.PlaceOrderContext() error = %v, want %v", err, ErrOrderNotPlaced)
+			}
+			if got := atomic.LoadInt32(&calls); got != 0 {
+				t.Errorf("server calls = %d, want 0", got)
+			}
+		})
+	}
+}
@@ -1,12 +1,14 @@
 package wexapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
@@ -18,6 +20,14 @@ const (
 	orderParamsCount = 2
 	orderRateIndex   = 0
 	orderAmountIndex = 1
+
+	pairsSeparator = "-"
+)
+
+var (
+	errNoPairs = errors.New("at least one pair required")
+
+	ignoreInvalidParam = Param{Key: "ignore_invalid", Value: "1"}
 )
 
 // InfoResponse for /info path.
@@ -42,8 +52,13 @@ type PairInfo struct {
 // maximum price, the minimum transaction size, whether
 // the pair is hidden, the commission for each pair.
 func (cli *Client) Info() (InfoResponse, error) {
+	return cli.InfoContext(context.Background())
+}
+
+// InfoContext is like Info but uses ctx for the request.
+func (cli *Client) InfoContext(ctx context.Context) (InfoResponse, error) {
 	infoResponse := InfoResponse{}
-	err := cli.publicRequest(&infoResponse, "info", nil)
+	err := cli.publicRequest(ctx, &infoResponse, "info", nil)
 	return infoResponse, err
 }
 
@@ -64,10 +79,45 @@ type Market struct {
 // active pairs, such as: the maximum price, the minimum
 // price, average price, trade volume, trade volume in
 // currency, the last trade, Buy and Sell price.
-func (cli *Client) Ticker(pair string) (Market, error) {
+func (cli *Client) Ticker(pair Pair) (Market, error) {
+	return cli.TickerContext(context.Background(), pair)
+}
+
+// TickerContext is like Ticker but uses ctx for the request.
+func (cli *Client) TickerContext(ctx context.Context, pair Pair) (Market, error) {
+	if err := pair.Validate(); err != nil {
+		return Market{}, err
+	}
+
 	tickerResponse := make(map[string]Market)
-	err := cli.publicRequest(&tickerResponse, fmt.Sprintf("ticker/%s", pair), nil)
-	return tickerResponse[pair], err
+	err := cli.publicRequest(ctx, &tickerResponse, "ticker", []Pair{pair})
+	return tickerResponse[pair.String()], err
+}
+
+// Tickers is like Ticker but requests multiple pairs at
+// once. Invalid pairs are ignored by the server, in that
+// case markets of the valid pairs are returned along with
+// DroppedPairsError.
+func (cli *Client) Tickers(pairs ...Pair) (map[Pair]Market, error) {
+	return cli.TickersContext(context.Background(), pairs...)
+}
+
+// TickersContext is like Tickers but uses ctx for the request.
+func (cli *Client) TickersContext(ctx context.Context, pairs ...Pair) (map[Pair]Market, error) {
+	if err := validatePairs(pairs); err != nil {
+		return nil, err
+	}
+
+	tickerResponse := make(map[Pair]Market)
+	err := cli.publicRequest(ctx, &tickerResponse, "ticker", pairs, ignoreInvalidParam)
+	if err != nil {
+		return tickerResponse, err
+	}
+
+	return tickerResponse, droppedPairs(pairs, func(pair Pair) bool {
+		_, ok := tickerResponse[pair]
+		return ok
+	})
 }
 
 // Order holds data about order.
@@ -110,68 +160,235 @@ type OrderBook struct {
 
 // Depth provides the information about active
 // orders on the pair.
-func (cli *Client) Depth(pair string, limit int) (OrderBook, error) {
+func (cli *Client) Depth(pair Pair, limit int) (OrderBook, error) {
+	return cli.DepthContext(context.Background(), pair, limit)
+}
+
+// DepthContext is like Depth but uses ctx for the request.
+func (cli *Client) DepthContext(ctx context.Context, pair Pair, limit int) (OrderBook, error) {
+	if err := pair.Validate(); err != nil {
+		return OrderBook{}, err
+	}
+
 	depthResponse := make(map[string]OrderBook)
-	param := param{key: "limit", value: strconv.Itoa(limit)}
-	err := cli.publicRequest(&depthResponse, fmt.Sprintf("depth/%s", pair), &param)
-	return depthResponse[pair], err
+	param := Param{Key: "limit", Value: strconv.Itoa(limit)}
+	err := cli.publicRequest(ctx, &depthResponse, "depth", []Pair{pair}, param)
+	return depthResponse[pair.String()], err
+}
+
+// Depths is like Depth but requests multiple pairs at
+// once. Invalid pairs are ignored by the server, in that
+// case order books of the valid pairs are returned along
+// with DroppedPairsError.
+func (cli *Client) Depths(limit int, pairs ...Pair) (map[Pair]OrderBook, error) {
+	return cli.DepthsContext(context.Background(), limit, pairs...)
+}
+
+// DepthsContext is like Depths but uses ctx for the request.
+func (cli *Client) DepthsContext(ctx context.Context, limit int, pairs ...Pair) (map[Pair]OrderBook, error) {
+	if err := validatePairs(pairs); err != nil {
+		return nil, err
+	}
+
+	depthResponse := make(map[Pair]OrderBook)
+	params := []Param{
+		Param{Key: "limit", Value: strconv.Itoa(limit)},
+		ignoreInvalidParam,
+	}
+	err := cli.publicRequest(ctx, &depthResponse, "depth", pairs, params...)
+	if err != nil {
+		return depthResponse, err
+	}
+
+	return depthResponse, droppedPairs(pairs, func(pair Pair) bool {
+		_, ok := depthResponse[pair]
+		return ok
+	})
 }
 
 // Trade holds data about trade.
 type Trade struct {
 	ID        uint64          `json:"tid"`
-	Type      string          `json:"type"`
+	Type      Side            `json:"type"`
 	Rate      decimal.Decimal `json:"price"`
 	Amount    decimal.Decimal `json:"amount"`
 	Timestamp unixTimestamp   `json:"timestamp"`
 }
 
 // Trades provides the information about the last trades.
-func (cli *Client) Trades(pair string, limit int) ([]Trade, error) {
+func (cli *Client) Trades(pair Pair, limit int) ([]Trade, error) {
+	return cli.TradesContext(context.Background(), pair, limit)
+}
+
+// TradesContext is like Trades but uses ctx for the request.
+func (cli *Client) TradesContext(ctx context.Context, pair Pair, limit int) ([]Trade, error) {
+	if err := pair.Validate(); err != nil {
+		return nil, err
+	}
+
 	tradeResponse := make(map[string][]Trade)
-	param := param{key: "limit", value: strconv.Itoa(limit)}
-	err := cli.publicRequest(&tradeResponse, fmt.Sprintf("trades/%s", pair), &param)
-	return tradeResponse[pair], err
+	param := Param{Key: "limit", Value: strconv.Itoa(limit)}
+	err := cli.publicRequest(ctx, &tradeResponse, "trades", []Pair{pair}, param)
+	return tradeResponse[pair.String()], err
+}
+
+// TradesMulti is like Trades but requests multiple pairs
+// at once. Invalid pairs are ignored by the server, in that
+// case trades of the valid pairs are returned along with
+// DroppedPairsError.
+func (cli *Client) TradesMulti(limit int, pairs ...Pair) (map[Pair][]Trade, error) {
+	return cli.TradesMultiContext(context.Background(), limit, pairs...)
 }
 
-func (cli *Client) publicRequest(result interface{}, method string, prm *param) error {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s", publicAPIEndpoint, method), nil)
+// TradesMultiContext is like TradesMulti but uses ctx for the request.
+func (cli *Client) TradesMultiContext(ctx context.Context, limit int, pairs ...Pair) (map[Pair][]Trade, error) {
+	if err := validatePairs(pairs); err != nil {
+		return nil, err
+	}
+
+	tradeResponse := make(map[Pair][]Trade)
+	params := []Param{
+		Param{Key: "limit", Value: strconv.Itoa(limit)},
+		ignoreInvalidParam,
+	}
+	err := cli.publicRequest(ctx, &tradeResponse, "trades", pairs, params...)
+	if err != nil {
+		return tradeResponse, err
+	}
+
+	return tradeResponse, droppedPairs(pairs, func(pair Pair) bool {
+		_, ok := tradeResponse[pair]
+		return ok
+	})
+}
+
+// DroppedPairsError holds pairs ignored by the server
+// as invalid in the multiple pairs request.
+type DroppedPairsError struct {
+	Pairs []Pair
+}
+
+func (e *DroppedPairsError) Error() string {
+	names := make([]string, len(e.Pairs))
+	for i, pair := range e.Pairs {
+		names[i] = pair.String()
+	}
+	return fmt.Sprintf("invalid pairs ignored: %s", strings.Join(names, ", "))
+}
+
+// Is reports whether target is ErrInvalidPair.
+func (e *DroppedPairsError) Is(target error) bool {
+	return target == ErrInvalidPair
+}
+
+// validatePairs checks there is at least
+// one pair and all of them are valid.
+func validatePairs(pairs []Pair) error {
+	if len(pairs) == 0 {
+		return errNoPairs
+	}
+
+	for _, pair := range pairs {
+		if err := pair.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func joinPairs(pairs []Pair) string {
+	names := make([]string, len(pairs))
+	for i, pair := range pairs {
+		names[i] = pair.String()
+	}
+	return strings.Join(names, pairsSeparator)
+}
+
+// droppedPairs returns DroppedPairsError if some
+// of the pairs are missing in the response.
+func droppedPairs(pairs []Pair, inResponse func(pair Pair) bool) error {
+	var dropped []Pair
+	for _, pair := range pairs {
+		if !inResponse(pair) {
+			dropped = append(dropped, pair)
+		}
+	}
+
+	if len(dropped) == 0 {
+		return nil
+	}
+
+	return &DroppedPairsError{
+		Pairs: dropped,
+	}
+}
+
+func (cli *Client) publicRequest(ctx context.Context, result interface{}, method string, pairs []Pair, params ...Param) error {
+	return cli.retryPolicy.do(ctx, isTransient, func() error {
+		if err := cli.publicLimiter.wait(ctx); err != nil {
+			return errors.Wrap(err, "rate limit")
+		}
+
+		req := &Request{
+			API:    PublicAPI,
+			Method: method,
+			Pairs:  append([]Pair(nil), pairs...),
+			Params: append([]Param(nil), params...),
+		}
+		resp, err := cli.handle(ctx, req, cli.sendPublic)
+		if err != nil {
+			return err
+		}
+
+		err = json.Unmarshal(resp.Result, result)
+		return errors.Wrap(err, "unmarshal to result")
+	})
+}
+
+func (cli *Client) sendPublic(ctx context.Context, r *Request) (*Response, error) {
+	path := r.Method
+	if len(r.Pairs) > 0 {
+		path += "/" + joinPairs(r.Pairs)
+	}
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s", cli.publicEndpoint, path), nil)
 	if err != nil {
-		return errors.Wrap(err, "request build")
+		return nil, errors.Wrap(err, "request build")
 	}
+	req = req.WithContext(ctx)
 
-	if prm != nil {
+	if len(r.Params) > 0 {
 		q := url.Values{}
-		q.Add(prm.key, prm.value)
+		for _, param := range r.Params {
+			q.Add(param.Key, param.Value)
+		}
 		req.URL.RawQuery = q.Encode()
 	}
 
 	resp, err := cli.httpClient.Do(req)
 	if err != nil {
-		return errors.Wrap(err, "do request")
+		return nil, errors.Wrap(err, "do request")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.Errorf("server respond with status code %d", resp.StatusCode)
+		return nil, &statusError{StatusCode: resp.StatusCode}
 	}
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return errors.Wrap(err, "read response body")
+		return nil, errors.Wrap(err, "read response body")
 	}
 
 	br := baseResponse{}
 	if err := json.Unmarshal(body, &br); err != nil {
-		return errors.Wrap(err, "unmarshal to base response")
+		return nil, errors.Wrap(err, "unmarshal to base response")
 	}
 
 	if !br.Success && br.Error != nil {
-		return errors.Errorf("server respond with error: %s", *br.Error)
+		return nil, newAPIError(r.Method, *br.Error, resp.StatusCode)
 	}
 
-	err = json.Unmarshal(body, result)
-	return errors.Wrap(err, "unmarshal to result")
+	return &Response{StatusCode: resp.StatusCode, Result: body}, nil
 }
 
 type baseResponse struct {
@@ -179,7 +396,3 @@ type baseResponse struct {
 	Error   *string         `json:"error"`
 	Return  json.RawMessage `json:"return"`
 }
-
-type param struct {
-	key, value string
-}
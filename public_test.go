@@ -1,6 +1,8 @@
 package wexapi
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -108,7 +110,7 @@ func TestClient_publicRequest(t *testing.T) {
 
 			cli := NewClient("", "", SetHTTPClient(httpClient))
 
-			err := cli.publicRequest(&baseResponse{}, "any", nil)
+			err := cli.publicRequest(context.Background(), &baseResponse{}, "any", nil)
 
 			if tt.wantErr {
 				if err == nil {
@@ -207,7 +209,7 @@ func TestClient_Ticker(t *testing.T) {
 			httpClient := testingHTTPClient(server)
 
 			cli := NewClient("", "", SetHTTPClient(httpClient))
-			got, err := cli.Ticker("btc_usd")
+			got, err := cli.Ticker(NewPair("btc", "usd"))
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Client.Ticker() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -257,7 +259,7 @@ func TestClient_Depth(t *testing.T) {
 			httpClient := testingHTTPClient(server)
 
 			cli := NewClient("", "", SetHTTPClient(httpClient))
-			got, err := cli.Depth("btc_usd", 1)
+			got, err := cli.Depth(NewPair("btc", "usd"), 1)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Client.Depth() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -284,7 +286,7 @@ func TestClient_Trades(t *testing.T) {
 			want: []Trade{
 				Trade{
 					ID:        4861261,
-					Type:      "ask",
+					Type:      Sell,
 					Rate:      decimal.NewFromFloatWithExponent(103.6, -1),
 					Amount:    decimal.NewFromFloatWithExponent(0.101, -3),
 					Timestamp: unixTimestamp(time.Unix(1370818007, 0)),
@@ -300,7 +302,7 @@ func TestClient_Trades(t *testing.T) {
 			httpClient := testingHTTPClient(server)
 
 			cli := NewClient("", "", SetHTTPClient(httpClient))
-			got, err := cli.Trades("btc_usd", 1)
+			got, err := cli.Trades(NewPair("btc", "usd"), 1)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Client.Trades() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -311,3 +313,168 @@ func TestClient_Trades(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_InfoContext(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		ctx     func() (context.Context, context.CancelFunc)
+		wantErr error
+	}{
+		{
+			name: "cancelled before request",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return cancelled, func() {}
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			name: "deadline exceeded in flight",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				<-release
+			}))
+			defer server.Close()
+			defer close(release)
+			httpClient := testingHTTPClient(server)
+
+			cli := NewClient("", "", SetHTTPClient(httpClient))
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			_, err := cli.InfoContext(ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Client.InfoContext() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_Tickers(t *testing.T) {
+	tests := []struct {
+		name        string
+		pairs       []Pair
+		wantPairs   []Pair
+		wantDropped []Pair
+		wantErr     bool
+	}{
+		{
+			name:      "all valid",
+			pairs:     []Pair{NewPair("btc", "usd")},
+			wantPairs: []Pair{NewPair("btc", "usd")},
+		},
+		{
+			name:        "invalid dropped",
+			pairs:       []Pair{NewPair("btc", "usd"), NewPair("usd", "btc")},
+			wantPairs:   []Pair{NewPair("btc", "usd")},
+			wantDropped: []Pair{NewPair("usd", "btc")},
+			wantErr:     true,
+		},
+		{
+			name:    "no pairs",
+			wantErr: true,
+		},
+		{
+			name:    "malformed pair",
+			pairs:   []Pair{NewPair("btc", "usd"), NewPair("BTC", "")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath, gotIgnoreInvalid string
+			server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				gotIgnoreInvalid = r.URL.Query().Get("ignore_invalid")
+				fmt.Fprint(w, tickerResponse)
+			}))
+			defer server.Close()
+			httpClient := testingHTTPClient(server)
+
+			cli := NewClient("", "", SetHTTPClient(httpClient))
+			got, err := cli.Tickers(tt.pairs...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Client.Tickers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && tt.wantDropped == nil {
+				if gotPath != "" {
+					t.Errorf("requested %s, want no request", gotPath)
+				}
+				return
+			}
+
+			if wantPath := "/api/3/ticker/" + joinPairs(tt.pairs); gotPath != wantPath {
+				t.Errorf("requested path = %s, want %s", gotPath, wantPath)
+			}
+			if gotIgnoreInvalid != "1" {
+				t.Errorf("ignore_invalid = %s, want 1", gotIgnoreInvalid)
+			}
+			for _, pair := range tt.wantPairs {
+				if _, ok := got[pair]; !ok {
+					t.Errorf("Client.Tickers() missing pair %s", pair)
+				}
+			}
+
+			var dropped *DroppedPairsError
+			if errors.As(err, &dropped) != (tt.wantDropped != nil) {
+				t.Fatalf("Client.Tickers() error = %v, want dropped pairs %v", err, tt.wantDropped)
+			}
+			if dropped != nil {
+				if !reflect.DeepEqual(dropped.Pairs, tt.wantDropped) {
+					t.Errorf("dropped pairs = %v, want %v", dropped.Pairs, tt.wantDropped)
+				}
+				if !errors.Is(err, ErrInvalidPair) {
+					t.Errorf("errors.Is(%v, ErrInvalidPair) = false, want true", err)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_Depths(t *testing.T) {
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, depthResponse)
+	}))
+	defer server.Close()
+	httpClient := testingHTTPClient(server)
+
+	cli := NewClient("", "", SetHTTPClient(httpClient))
+	btcUSD, ltcUSD := NewPair("btc", "usd"), NewPair("ltc", "usd")
+	got, err := cli.Depths(1, btcUSD, ltcUSD)
+
+	var dropped *DroppedPairsError
+	if !errors.As(err, &dropped) || !reflect.DeepEqual(dropped.Pairs, []Pair{ltcUSD}) {
+		t.Fatalf("Client.Depths() error = %v, want ltc_usd dropped", err)
+	}
+	if len(got[btcUSD].Asks) != 1 || len(got[btcUSD].Bids) != 1 {
+		t.Errorf("Client.Depths() = %v, want btc_usd order book", got)
+	}
+}
+
+func TestClient_TradesMulti(t *testing.T) {
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, tradesResponse)
+	}))
+	defer server.Close()
+	httpClient := testingHTTPClient(server)
+
+	cli := NewClient("", "", SetHTTPClient(httpClient))
+	pair := NewPair("btc", "usd")
+	got, err := cli.TradesMulti(1, pair)
+	if err != nil {
+		t.Fatalf("Client.TradesMulti() error = %v", err)
+	}
+	if len(got[pair]) != 1 || got[pair][0].ID != 4861261 {
+		t.Errorf("Client.TradesMulti() = %v, want btc_usd trades", got)
+	}
+}
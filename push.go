@@ -0,0 +1,416 @@
+package wexapi
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+const (
+	pushAPIEndpoint = "wss://ws-eu.pusher.com/app/ee987526a24ba107824c?protocol=7"
+
+	defaultMinBackoff = time.Second
+	defaultMaxBackoff = time.Minute
+
+	defaultActivityTimeout = 120 * time.Second
+	defaultPongTimeout     = 30 * time.Second
+
+	pushEventsBuffer = 128
+
+	pusherConnectionEstablished = "pusher:connection_established"
+	pusherSubscribe             = "pusher:subscribe"
+	pusherPing                  = "pusher:ping"
+	pusherPong                  = "pusher:pong"
+	pusherError                 = "pusher:error"
+)
+
+// Push channel kinds, channel name is a pair
+// and a kind joined with dot, e.g. btc_usd.depth.
+const (
+	PushDepth  = "depth"
+	PushTrades = "trades"
+	PushChart  = "chart"
+)
+
+// PushChannel returns name of the push api channel
+// of the kind for the pair.
+func PushChannel(pair Pair, kind string) string {
+	return pair.String() + "." + kind
+}
+
+// PushEvent holds data received from the push api channel.
+// Depth holds changed levels of the order book, where zero
+// amount means the level is removed. Trades holds new trades,
+// only Type, Rate and Amount are set. Data of the chart and
+// unknown channels is left raw in Chart.
+type PushEvent struct {
+	Channel string
+	Pair    Pair
+	Kind    string
+	Depth   OrderBook
+	Trades  []Trade
+	Chart   json.RawMessage
+}
+
+// StreamOption for stream initializer.
+type StreamOption func(*Stream)
+
+// SetStreamEndpoint sets websocket url of the push api.
+func SetStreamEndpoint(endpoint string) StreamOption {
+	return func(s *Stream) {
+		s.endpoint = endpoint
+	}
+}
+
+// SetStreamDialer sets websocket dialer for the stream.
+func SetStreamDialer(dialer *websocket.Dialer) StreamOption {
+	return func(s *Stream) {
+		s.dialer = dialer
+	}
+}
+
+// SetStreamBackoff sets min and max delays between
+// reconnects, delay doubles on every failed attempt.
+// Non positive min means 1 second, max lower than
+// min is raised to it.
+func SetStreamBackoff(min, max time.Duration) StreamOption {
+	return func(s *Stream) {
+		if min <= 0 {
+			min = defaultMinBackoff
+		}
+		if max < min {
+			max = min
+		}
+		s.minBackoff = min
+		s.maxBackoff = max
+	}
+}
+
+// SetStreamTimeouts sets timeout of the connection inactivity
+// after which ping is sent, the lower of it and the one sent by
+// the server is used, and timeout of waiting for the response
+// after which connection is considered dead.
+func SetStreamTimeouts(activity, pong time.Duration) StreamOption {
+	return func(s *Stream) {
+		s.activityTimeout = activity
+		s.pongTimeout = pong
+	}
+}
+
+// Stream receives events from the push api channels
+// and reconnects on failures.
+// Use NewStream to initialize one.
+type Stream struct {
+	endpoint   string
+	dialer     *websocket.Dialer
+	channels   []string
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	events     chan PushEvent
+
+	activityTimeout time.Duration
+	pongTimeout     time.Duration
+
+	mu  sync.RWMutex
+	err error
+}
+
+// NewStream returns stream subscribing to the channels.
+func NewStream(channels []string, options ...StreamOption) *Stream {
+	s := Stream{
+		endpoint:   pushAPIEndpoint,
+		dialer:     websocket.DefaultDialer,
+		channels:   channels,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+		events:     make(chan PushEvent, pushEventsBuffer),
+
+		activityTimeout: defaultActivityTimeout,
+		pongTimeout:     defaultPongTimeout,
+	}
+
+	for _, option := range options {
+		option(&s)
+	}
+
+	return &s
+}
+
+// Events returns channel of the received events,
+// stream waits for them to be consumed, so it must be read.
+func (s *Stream) Events() <-chan PushEvent {
+	return s.events
+}
+
+// Err returns error caused the last reconnect
+// or message which couldn't be decoded.
+func (s *Stream) Err() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.err
+}
+
+// Run receives events until ctx is done, reconnecting
+// with exponential backoff if connection fails.
+func (s *Stream) Run(ctx context.Context) error {
+	backoff := s.minBackoff
+
+	for {
+		established, err := s.session(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+
+		if established {
+			backoff = s.minBackoff
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+	}
+}
+
+// session connects, subscribes to the channels and reads
+// events until connection fails, established reports
+// whether connection was established by the server.
+func (s *Stream) session(ctx context.Context) (established bool, err error) {
+	conn, _, err := s.dialer.DialContext(ctx, s.endpoint, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "dial")
+	}
+	defer conn.Close()
+
+	var writeMu sync.Mutex
+	write := func(msg pusherMessage) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+
+		return conn.WriteJSON(msg)
+	}
+
+	activityTimeout := s.activityTimeout
+	activity := make(chan time.Duration, 1)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	go keepAlive(write, activity, done, activityTimeout)
+
+	for {
+		// Connection is dead if nothing, even pong on
+		// ping sent when idle, came in time.
+		if err := conn.SetReadDeadline(time.Now().Add(activityTimeout + s.pongTimeout)); err != nil {
+			return established, errors.Wrap(err, "set read deadline")
+		}
+
+		var msg pusherMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return established, errors.Wrap(err, "read message")
+		}
+
+		switch msg.Event {
+		case pusherConnectionEstablished:
+			established = true
+			if timeout, ok := serverActivityTimeout(msg); ok && timeout < activityTimeout {
+				activityTimeout = timeout
+			}
+			for _, channel := range s.channels {
+				if err := write(subscribeMessage(channel)); err != nil {
+					return established, errors.Wrapf(err, "subscribe %s", channel)
+				}
+			}
+		case pusherPing:
+			if err := write(pusherMessage{Event: pusherPong, Data: json.RawMessage("{}")}); err != nil {
+				return established, errors.Wrap(err, "pong")
+			}
+		case pusherError:
+			return established, errors.Errorf("pusher error: %s", msg.Data)
+		default:
+			if msg.Channel == "" || strings.HasPrefix(msg.Event, "pusher") {
+				break
+			}
+
+			// Message which can't be decoded is skipped,
+			// so it doesn't break the whole session.
+			event, err := decodePushEvent(msg)
+			if err != nil {
+				s.mu.Lock()
+				s.err = errors.Wrapf(err, "decode %s event", msg.Channel)
+				s.mu.Unlock()
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return established, ctx.Err()
+			case s.events <- event:
+			}
+		}
+
+		select {
+		case activity <- activityTimeout:
+		default:
+		}
+	}
+}
+
+// keepAlive pings the server when connection is idle for
+// the activity timeout, which is updated on every activity.
+func keepAlive(write func(pusherMessage) error, activity <-chan time.Duration, done <-chan struct{}, timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case timeout = <-activity:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		case <-timer.C:
+			// Failed write breaks the connection,
+			// so it's handled by the reader.
+			write(pusherMessage{Event: pusherPing, Data: json.RawMessage("{}")})
+		}
+		timer.Reset(timeout)
+	}
+}
+
+// serverActivityTimeout returns activity timeout from
+// the connection established message data.
+func serverActivityTimeout(msg pusherMessage) (time.Duration, bool) {
+	data, err := msg.payload()
+	if err != nil {
+		return 0, false
+	}
+
+	var established struct {
+		ActivityTimeout int `json:"activity_timeout"`
+	}
+	if err := json.Unmarshal(data, &established); err != nil || established.ActivityTimeout <= 0 {
+		return 0, false
+	}
+	return time.Duration(established.ActivityTimeout) * time.Second, true
+}
+
+type pusherMessage struct {
+	Event   string          `json:"event"`
+	Channel string          `json:"channel,omitempty"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// payload returns data of the message, pusher
+// sends it as a json encoded string.
+func (msg pusherMessage) payload() ([]byte, error) {
+	if len(msg.Data) == 0 || msg.Data[0] != '"' {
+		return msg.Data, nil
+	}
+
+	var data string
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		return nil, err
+	}
+	return []byte(data), nil
+}
+
+func subscribeMessage(channel string) pusherMessage {
+	data, _ := json.Marshal(map[string]string{"channel": channel})
+	return pusherMessage{
+		Event: pusherSubscribe,
+		Data:  data,
+	}
+}
+
+type pushDepth struct {
+	Asks []Order `json:"ask"`
+	Bids []Order `json:"bid"`
+}
+
+// pushTrade parses ["buy","103.6","0.101"] format into Trade.
+type pushTrade Trade
+
+func (trade *pushTrade) UnmarshalJSON(data []byte) error {
+	var fields [3]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(fields[0], &trade.Type); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(fields[1], &trade.Rate); err != nil {
+		return err
+	}
+	return json.Unmarshal(fields[2], &trade.Amount)
+}
+
+func decodePushEvent(msg pusherMessage) (PushEvent, error) {
+	event := PushEvent{
+		Channel: msg.Channel,
+	}
+
+	dot := strings.LastIndex(msg.Channel, ".")
+	if dot < 0 {
+		return event, errors.Errorf("invalid channel %s", msg.Channel)
+	}
+	pair, err := ParsePair(msg.Channel[:dot])
+	if err != nil {
+		return event, err
+	}
+	event.Pair, event.Kind = pair, msg.Channel[dot+1:]
+
+	data, err := msg.payload()
+	if err != nil {
+		return event, errors.Wrap(err, "unmarshal data")
+	}
+
+	switch event.Kind {
+	case PushDepth:
+		depth := pushDepth{}
+		if err := json.Unmarshal(data, &depth); err != nil {
+			return event, errors.Wrap(err, "unmarshal depth")
+		}
+		event.Depth = OrderBook(depth)
+	case PushTrades:
+		var trades []pushTrade
+		if err := json.Unmarshal(data, &trades); err != nil {
+			return event, errors.Wrap(err, "unmarshal trades")
+		}
+		event.Trades = make([]Trade, len(trades))
+		for i, trade := range trades {
+			event.Trades[i] = Trade(trade)
+		}
+	default:
+		event.Chart = json.RawMessage(data)
+	}
+
+	return event, nil
+}
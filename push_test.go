@@ -0,0 +1,324 @@
+package wexapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	pushEstablishedMessage = `{"event":"pusher:connection_established","data":"{\"socket_id\":\"1.1\",\"activity_timeout\":120}"}`
+	pushDepthMessage       = `{"event":"depth","channel":"btc_usd.depth","data":"{\"ask\":[[\"103.4\",\"0.5\"]],\"bid\":[[103.2,0]]}"}`
+	pushTradesMessage      = `{"event":"trades","channel":"btc_usd.trades","data":"[[\"sell\",\"103.3\",\"0.1\"]]"}`
+)
+
+// pushServer is a stand-in of the pusher server which
+// sends messages to every connection and drops it.
+type pushServer struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	connections int
+	subscribed  []string
+}
+
+func newPushServer(t *testing.T, messages ...string) *pushServer {
+	server := pushServer{}
+	upgrader := websocket.Upgrader{}
+
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		server.mu.Lock()
+		server.connections++
+		server.mu.Unlock()
+
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(pushEstablishedMessage)); err != nil {
+			return
+		}
+
+		var msg pusherMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		server.mu.Lock()
+		server.subscribed = append(server.subscribed, string(msg.Data))
+		server.mu.Unlock()
+
+		for _, message := range messages {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+				return
+			}
+		}
+	}))
+
+	return &server
+}
+
+func (s *pushServer) url() string {
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+func TestStream(t *testing.T) {
+	server := newPushServer(t, pushDepthMessage, pushTradesMessage)
+	defer server.Close()
+
+	pair := NewPair("btc", "usd")
+	stream := NewStream(
+		[]string{PushChannel(pair, PushDepth)},
+		SetStreamEndpoint(server.url()),
+		SetStreamBackoff(10*time.Millisecond, 20*time.Millisecond),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- stream.Run(ctx)
+	}()
+
+	// Server drops connection after messages, so the
+	// second pair of events comes after reconnect.
+	for i := 0; i < 2; i++ {
+		depth := receivePushEvent(t, stream)
+		if depth.Pair != pair || depth.Kind != PushDepth {
+			t.Fatalf("got %s %s event, want btc_usd depth", depth.Pair, depth.Kind)
+		}
+		if len(depth.Depth.Asks) != 1 || !depth.Depth.Asks[0].Rate.Equal(decimal.RequireFromString("103.4")) {
+			t.Errorf("depth asks = %v, want 103.4 level", depth.Depth.Asks)
+		}
+		if len(depth.Depth.Bids) != 1 || !depth.Depth.Bids[0].Amount.IsZero() {
+			t.Errorf("depth bids = %v, want removed 103.2 level", depth.Depth.Bids)
+		}
+
+		trades := receivePushEvent(t, stream)
+		if trades.Kind != PushTrades || len(trades.Trades) != 1 {
+			t.Fatalf("got %s event with %d trades, want 1 trade", trades.Kind, len(trades.Trades))
+		}
+		trade := trades.Trades[0]
+		if trade.Type != Sell || !trade.Rate.Equal(decimal.RequireFromString("103.3")) || !trade.Amount.Equal(decimal.RequireFromString("0.1")) {
+			t.Errorf("trade = %v, want sell 103.3 0.1", trade)
+		}
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Stream.Run() error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stream.Run() didn't stop")
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.connections < 2 {
+		t.Errorf("server got %d connections, want reconnect", server.connections)
+	}
+	if server.subscribed[0] != `{"channel":"btc_usd.depth"}` {
+		t.Errorf("subscribed with %s, want btc_usd.depth channel", server.subscribed[0])
+	}
+}
+
+func receivePushEvent(t *testing.T, stream *Stream) PushEvent {
+	t.Helper()
+
+	select {
+	case event := <-stream.Events():
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("event not received")
+	}
+	return PushEvent{}
+}
+
+func Test_decodePushEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     pusherMessage
+		wantErr bool
+	}{
+		{
+			name: "chart",
+			msg: pusherMessage{
+				Event:   "tick",
+				Channel: "btc_usd.chart",
+				Data:    []byte(`"[[1,2]]"`),
+			},
+		},
+		{
+			name: "invalid channel",
+			msg: pusherMessage{
+				Event:   "depth",
+				Channel: "depth",
+				Data:    []byte(`"{}"`),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid trades",
+			msg: pusherMessage{
+				Event:   "trades",
+				Channel: "btc_usd.trades",
+				Data:    []byte(`"[[\"bid\"]]"`),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodePushEvent(tt.msg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodePushEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// newIdlePushServer returns pusher server stand-in which
+// sends messages and keeps connection open, answering
+// pings with pongs if pong is set.
+func newIdlePushServer(t *testing.T, pong bool, messages ...string) (*pushServer, *int32) {
+	server := pushServer{}
+	upgrader := websocket.Upgrader{}
+	var pings int32
+
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		server.mu.Lock()
+		server.connections++
+		server.mu.Unlock()
+
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(pushEstablishedMessage)); err != nil {
+			return
+		}
+		for _, message := range messages {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+				return
+			}
+		}
+
+		for {
+			var msg pusherMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			if msg.Event != pusherPing {
+				continue
+			}
+			atomic.AddInt32(&pings, 1)
+			if pong {
+				conn.WriteJSON(pusherMessage{Event: pusherPong, Data: json.RawMessage("{}")})
+			}
+		}
+	}))
+
+	return &server, &pings
+}
+
+func TestStream_keepAlive(t *testing.T) {
+	tests := []struct {
+		name      string
+		pong      bool
+		reconnect bool
+	}{
+		{name: "pong received", pong: true, reconnect: false},
+		{name: "connection dead", pong: false, reconnect: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, pings := newIdlePushServer(t, tt.pong)
+			defer server.Close()
+
+			stream := NewStream(
+				[]string{PushChannel(NewPair("btc", "usd"), PushDepth)},
+				SetStreamEndpoint(server.url()),
+				SetStreamBackoff(10*time.Millisecond, 20*time.Millisecond),
+				SetStreamTimeouts(30*time.Millisecond, 50*time.Millisecond),
+			)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+			defer cancel()
+			stream.Run(ctx)
+
+			if atomic.LoadInt32(pings) == 0 {
+				t.Error("server got no pings")
+			}
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			if reconnected := server.connections > 1; reconnected != tt.reconnect {
+				t.Errorf("server got %d connections, want reconnect %v", server.connections, tt.reconnect)
+			}
+		})
+	}
+}
+
+func TestStream_invalidMessage(t *testing.T) {
+	invalid := `{"event":"trades","channel":"btc_usd.trades","data":"[[\"bid\"]]"}`
+	server, _ := newIdlePushServer(t, true, invalid, pushDepthMessage)
+	defer server.Close()
+
+	stream := NewStream(
+		[]string{PushChannel(NewPair("btc", "usd"), PushDepth)},
+		SetStreamEndpoint(server.url()),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go stream.Run(ctx)
+
+	if event := receivePushEvent(t, stream); event.Kind != PushDepth {
+		t.Errorf("got %s event, want depth event after invalid one", event.Kind)
+	}
+	if err := stream.Err(); err == nil || !strings.Contains(err.Error(), "decode btc_usd.trades event") {
+		t.Errorf("Stream.Err() = %v, want decode error", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.connections != 1 {
+		t.Errorf("server got %d connections, want 1", server.connections)
+	}
+}
+
+func TestSetStreamBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max time.Duration
+		wantMin  time.Duration
+		wantMax  time.Duration
+	}{
+		{name: "valid", min: time.Second, max: time.Minute, wantMin: time.Second, wantMax: time.Minute},
+		{name: "zero min", min: 0, max: time.Minute, wantMin: defaultMinBackoff, wantMax: time.Minute},
+		{name: "max below min", min: time.Second, max: time.Millisecond, wantMin: time.Second, wantMax: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := NewStream(nil, SetStreamBackoff(tt.min, tt.max))
+			if stream.minBackoff != tt.wantMin || stream.maxBackoff != tt.wantMax {
+				t.Errorf("backoff = %v-%v, want %v-%v", stream.minBackoff, stream.maxBackoff, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package wexapi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SetPublicRateLimit limits public api requests to requests
+// per period, allowing bursts of up to burst requests.
+// Requests over the limit wait for their turn, zero
+// requests or period turns limiting off.
+func SetPublicRateLimit(requests int, per time.Duration, burst int) Option {
+	return func(cli *Client) {
+		cli.publicLimiter = newRateLimiter(requests, per, burst)
+	}
+}
+
+// SetTradeRateLimit limits trade api requests to requests
+// per period, allowing bursts of up to burst requests.
+// Requests over the limit wait for their turn, zero
+// requests or period turns limiting off.
+func SetTradeRateLimit(requests int, per time.Duration, burst int) Option {
+	return func(cli *Client) {
+		cli.tradeLimiter = newRateLimiter(requests, per, burst)
+	}
+}
+
+// RateLimitStats holds number of the requests delayed
+// by rate limiters and total time they waited.
+type RateLimitStats struct {
+	PublicThrottled     uint64
+	PublicThrottledTime time.Duration
+	TradeThrottled      uint64
+	TradeThrottledTime  time.Duration
+}
+
+// RateLimitStats returns statistics of the rate limiters.
+func (cli *Client) RateLimitStats() RateLimitStats {
+	stats := RateLimitStats{}
+	stats.PublicThrottled, stats.PublicThrottledTime = cli.publicLimiter.stats()
+	stats.TradeThrottled, stats.TradeThrottledTime = cli.tradeLimiter.stats()
+	return stats
+}
+
+// rateLimiter is a token bucket, nil limiter doesn't limit.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration // time to get one token
+	burst    float64
+	tokens   float64
+	last     time.Time
+
+	throttled     uint64
+	throttledTime time.Duration
+}
+
+func newRateLimiter(requests int, per time.Duration, burst int) *rateLimiter {
+	if requests <= 0 || per <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimiter{
+		interval: per / time.Duration(requests),
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+// wait takes a token, waiting for it if bucket is empty.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	delay := l.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	case <-timer.C:
+	}
+
+	l.mu.Lock()
+	l.throttled++
+	l.throttledTime += delay
+	l.mu.Unlock()
+
+	return nil
+}
+
+// reserve takes a token and returns time to wait until
+// it's available, tokens go below zero for waiters.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens * float64(l.interval))
+}
+
+// cancel returns reserved token.
+func (l *rateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens++
+}
+
+func (l *rateLimiter) stats() (uint64, time.Duration) {
+	if l == nil {
+		return 0, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.throttled, l.throttledTime
+}
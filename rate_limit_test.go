@@ -0,0 +1,74 @@
+package wexapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_RateLimit(t *testing.T) {
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/tapi" {
+			fmt.Fprint(w, `{"success":1,"return":{}}`)
+			return
+		}
+		fmt.Fprint(w, tickerResponse)
+	}))
+	defer server.Close()
+	httpClient := testingHTTPClient(server)
+
+	cli := NewClient("key", "secret",
+		SetHTTPClient(httpClient),
+		SetPublicRateLimit(1, 50*time.Millisecond, 2),
+		SetTradeRateLimit(1, 50*time.Millisecond, 1),
+	)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := cli.Ticker(NewPair("btc", "usd")); err != nil {
+			t.Fatalf("Client.Ticker() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("3 requests with burst 2 took %v, want at least 40ms", elapsed)
+	}
+
+	if _, err := cli.ActiveOrders(Pair{}); err != nil {
+		t.Fatalf("Client.ActiveOrders() error = %v", err)
+	}
+
+	stats := cli.RateLimitStats()
+	if stats.PublicThrottled != 1 || stats.PublicThrottledTime <= 0 {
+		t.Errorf("public stats = %d %v, want 1 throttled request", stats.PublicThrottled, stats.PublicThrottledTime)
+	}
+	if stats.TradeThrottled != 0 || stats.TradeThrottledTime != 0 {
+		t.Errorf("trade stats = %d %v, want no throttled requests", stats.TradeThrottled, stats.TradeThrottledTime)
+	}
+}
+
+func TestRateLimiter_wait(t *testing.T) {
+	limiter := newRateLimiter(1, time.Hour, 1)
+	if err := limiter.wait(context.Background()); err != nil {
+		t.Fatalf("rateLimiter.wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("rateLimiter.wait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if limiter.tokens < -0.01 || limiter.tokens > 0.01 {
+		t.Errorf("rateLimiter.tokens = %v, want canceled token returned", limiter.tokens)
+	}
+	if count, _ := limiter.stats(); count != 0 {
+		t.Errorf("rateLimiter.stats() = %d, want 0", count)
+	}
+
+	var nilLimiter *rateLimiter
+	if err := nilLimiter.wait(ctx); err != nil {
+		t.Errorf("nil rateLimiter.wait() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,225 @@
+package wexapi
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PairChange describes a change of the pair info
+// noticed by PairRegistry on refresh.
+type PairChange struct {
+	Pair     Pair
+	Old, New PairInfo
+	Added    bool // pair appeared, Old is zero
+	Removed  bool // pair disappeared, New is zero
+}
+
+// PairRegistry caches pairs info returned by Info and
+// refreshes it in the background.
+// Use NewPairRegistry to initialize one.
+type PairRegistry struct {
+	cli *Client
+
+	// refreshMu serializes refreshes, so an older
+	// response doesn't overwrite a newer one.
+	refreshMu sync.Mutex
+
+	mu          sync.RWMutex
+	pairs       map[string]PairInfo
+	subscribers []func(PairChange)
+	err         error
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewPairRegistry requests pairs info and returns registry
+// which refreshes it every interval until ctx is done or
+// Close is called. Background refresh is disabled if
+// interval isn't positive.
+func NewPairRegistry(ctx context.Context, cli *Client, interval time.Duration) (*PairRegistry, error) {
+	registry := PairRegistry{
+		cli:  cli,
+		done: make(chan struct{}),
+	}
+
+	if err := registry.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	ctx, registry.cancel = context.WithCancel(ctx)
+	if interval <= 0 {
+		close(registry.done)
+		return &registry, nil
+	}
+
+	go func() {
+		defer close(registry.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				err := registry.Refresh(ctx)
+				registry.mu.Lock()
+				registry.err = err
+				registry.mu.Unlock()
+			}
+		}
+	}()
+
+	return &registry, nil
+}
+
+// Close stops background refresh.
+func (r *PairRegistry) Close() {
+	r.cancel()
+	<-r.done
+}
+
+// Err returns error of the last background refresh.
+func (r *PairRegistry) Err() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.err
+}
+
+// Subscribe registers fn to be called for every
+// change noticed on refresh. It's called from the
+// refreshing goroutine, so it shouldn't block.
+func (r *PairRegistry) Subscribe(fn func(PairChange)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subscribers = append(r.subscribers, fn)
+}
+
+// Refresh requests pairs info and notifies subscribers
+// about changes. Concurrent refreshes run one at a time.
+func (r *PairRegistry) Refresh(ctx context.Context) error {
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+
+	info, err := r.cli.InfoContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	old := r.pairs
+	r.pairs = info.Pairs
+	subscribers := append([]func(PairChange){}, r.subscribers...)
+	r.mu.Unlock()
+
+	if old == nil {
+		return nil
+	}
+
+	for _, change := range pairChanges(old, info.Pairs) {
+		for _, fn := range subscribers {
+			fn(change)
+		}
+	}
+
+	return nil
+}
+
+// Lookup returns info of the pair.
+func (r *PairRegistry) Lookup(pair Pair) (PairInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	info, ok := r.pairs[pair.String()]
+	return info, ok
+}
+
+// Visible returns sorted list of the pairs
+// which aren't hidden.
+func (r *PairRegistry) Visible() []Pair {
+	return r.filter(func(info PairInfo) bool {
+		return !bool(info.Hidden)
+	})
+}
+
+// Hidden returns sorted list of the hidden pairs.
+func (r *PairRegistry) Hidden() []Pair {
+	return r.filter(func(info PairInfo) bool {
+		return bool(info.Hidden)
+	})
+}
+
+func (r *PairRegistry) filter(fn func(info PairInfo) bool) []Pair {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.pairs))
+	for name, info := range r.pairs {
+		if fn(info) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	pairs := make([]Pair, 0, len(names))
+	for _, name := range names {
+		pair, err := ParsePair(name)
+		if err != nil {
+			continue
+		}
+		pairs = append(pairs, pair)
+	}
+
+	return pairs
+}
+
+// pairChanges returns sorted by pair list of changes
+// between old and current pairs info.
+func pairChanges(old, current map[string]PairInfo) []PairChange {
+	names := make([]string, 0, len(old)+len(current))
+	for name := range old {
+		names = append(names, name)
+	}
+	for name := range current {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []PairChange
+	for _, name := range names {
+		pair, err := ParsePair(name)
+		if err != nil {
+			continue
+		}
+
+		oldInfo, inOld := old[name]
+		newInfo, inNew := current[name]
+		switch {
+		case !inOld:
+			changes = append(changes, PairChange{Pair: pair, New: newInfo, Added: true})
+		case !inNew:
+			changes = append(changes, PairChange{Pair: pair, Old: oldInfo, Removed: true})
+		case !pairInfoEqual(oldInfo, newInfo):
+			changes = append(changes, PairChange{Pair: pair, Old: oldInfo, New: newInfo})
+		}
+	}
+
+	return changes
+}
+
+func pairInfoEqual(a, b PairInfo) bool {
+	return a.DecimalPlaces == b.DecimalPlaces &&
+		a.MinPrice.Equal(b.MinPrice) &&
+		a.MaxPrice.Equal(b.MaxPrice) &&
+		a.MinAmount.Equal(b.MinAmount) &&
+		a.Fee.Equal(b.Fee) &&
+		a.Hidden == b.Hidden
+}
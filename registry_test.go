@@ -0,0 +1,151 @@
+package wexapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	registryInfoResponse = `{
+		"server_time":1370814956,
+		"pairs":{
+			"btc_usd":{"decimal_places":3,"min_price":0.1,"max_price":400,"min_amount":0.01,"hidden":0,"fee":0.2},
+			"ltc_usd":{"decimal_places":3,"min_price":0.1,"max_price":400,"min_amount":0.1,"hidden":1,"fee":0.2}
+		}
+	}`
+	registryUpdatedInfoResponse = `{
+		"server_time":1370814966,
+		"pairs":{
+			"btc_usd":{"decimal_places":3,"min_price":0.1,"max_price":400,"min_amount":0.01,"hidden":0,"fee":0.1},
+			"eth_usd":{"decimal_places":3,"min_price":0.1,"max_price":400,"min_amount":0.1,"hidden":0,"fee":0.2}
+		}
+	}`
+)
+
+func TestPairRegistry(t *testing.T) {
+	var mu sync.Mutex
+	response := registryInfoResponse
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprint(w, response)
+	}))
+	defer server.Close()
+	httpClient := testingHTTPClient(server)
+
+	cli := NewClient("", "", SetHTTPClient(httpClient))
+	registry, err := NewPairRegistry(context.Background(), cli, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewPairRegistry() error = %v", err)
+	}
+	defer registry.Close()
+
+	info, ok := registry.Lookup(NewPair("btc", "usd"))
+	if !ok || info.Fee.String() != "0.2" {
+		t.Errorf("PairRegistry.Lookup() = %v, %v, want btc_usd info", info, ok)
+	}
+	if got, want := registry.Visible(), []Pair{NewPair("btc", "usd")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PairRegistry.Visible() = %v, want %v", got, want)
+	}
+	if got, want := registry.Hidden(), []Pair{NewPair("ltc", "usd")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PairRegistry.Hidden() = %v, want %v", got, want)
+	}
+
+	changes := make(chan PairChange, 10)
+	registry.Subscribe(func(change PairChange) {
+		changes <- change
+	})
+
+	mu.Lock()
+	response = registryUpdatedInfoResponse
+	mu.Unlock()
+
+	var got []PairChange
+	for len(got) < 3 {
+		select {
+		case change := <-changes:
+			got = append(got, change)
+		case <-time.After(time.Second):
+			t.Fatalf("got changes %v, want 3", got)
+		}
+	}
+
+	if got[0].Pair != NewPair("btc", "usd") || got[0].Old.Fee.String() != "0.2" || got[0].New.Fee.String() != "0.1" {
+		t.Errorf("first change = %+v, want btc_usd fee change", got[0])
+	}
+	if got[1].Pair != NewPair("eth", "usd") || !got[1].Added {
+		t.Errorf("second change = %+v, want eth_usd added", got[1])
+	}
+	if got[2].Pair != NewPair("ltc", "usd") || !got[2].Removed {
+		t.Errorf("third change = %+v, want ltc_usd removed", got[2])
+	}
+
+	if _, ok := registry.Lookup(NewPair("ltc", "usd")); ok {
+		t.Error("PairRegistry.Lookup() found removed pair")
+	}
+}
+
+func TestNewPairRegistry_error(t *testing.T) {
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	httpClient := testingHTTPClient(server)
+
+	cli := NewClient("", "", SetHTTPClient(httpClient))
+	if _, err := NewPairRegistry(context.Background(), cli, time.Minute); err == nil {
+		t.Error("NewPairRegistry() error = nil, want error")
+	}
+}
+
+func TestPairRegistry_concurrentRefresh(t *testing.T) {
+	var mu sync.Mutex
+	var calls int
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		call := calls
+		mu.Unlock()
+
+		switch call {
+		case 1:
+			fmt.Fprint(w, registryInfoResponse)
+		case 2:
+			// Slow response with the stale info.
+			time.Sleep(100 * time.Millisecond)
+			fmt.Fprint(w, registryInfoResponse)
+		default:
+			fmt.Fprint(w, registryUpdatedInfoResponse)
+		}
+	}))
+	defer server.Close()
+	httpClient := testingHTTPClient(server)
+
+	cli := NewClient("", "", SetHTTPClient(httpClient))
+	registry, err := NewPairRegistry(context.Background(), cli, 0)
+	if err != nil {
+		t.Fatalf("NewPairRegistry() error = %v", err)
+	}
+	defer registry.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := registry.Refresh(context.Background()); err != nil {
+				t.Errorf("PairRegistry.Refresh() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if info, ok := registry.Lookup(NewPair("btc", "usd")); !ok || info.Fee.String() != "0.1" {
+		t.Errorf("PairRegistry.Lookup() = %v, %v, want btc_usd info of the last response", info, ok)
+	}
+}
@@ -0,0 +1,138 @@
+package wexapi
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy configures retries of the failed requests.
+// Public and read only trade requests are retried on
+// network errors and 5xx or 429 status codes, the others,
+// e.g. Trade or WithdrawCoin, only if connection to the
+// server failed, so the request wasn't sent.
+type RetryPolicy struct {
+	// MaxRetries is a number of retries after the first attempt.
+	MaxRetries int
+	// MinBackoff is a delay before the first retry, it doubles
+	// for each next one up to MaxBackoff. Actual delay is
+	// random between a half and a full backoff. Zero value
+	// means 100ms, so failed requests aren't hammered.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// defaultRetryBackoff is used when RetryPolicy.MinBackoff isn't set.
+const defaultRetryBackoff = 100 * time.Millisecond
+
+// SetRetryPolicy sets policy of retrying failed requests,
+// requests aren't retried by default.
+func SetRetryPolicy(policy RetryPolicy) Option {
+	return func(cli *Client) {
+		cli.retryPolicy = &policy
+	}
+}
+
+// idempotentTradeMethods are trade api methods
+// which don't change anything, so safe to retry.
+var idempotentTradeMethods = map[string]bool{
+	"getInfo":            true,
+	"ActiveOrders":       true,
+	"OrderInfo":          true,
+	"TradeHistory":       true,
+	"TransHistory":       true,
+	"CoinDepositAddress": true,
+}
+
+// statusError caused by non 200 response status code.
+type statusError struct {
+	StatusCode int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("server respond with status code %d", e.StatusCode)
+}
+
+// do calls request until it succeeds, fails with error which
+// isn't retryable or retries are exhausted. Nil policy calls
+// request once.
+func (p *RetryPolicy) do(ctx context.Context, retryable func(error) bool, request func() error) error {
+	err := request()
+	if p == nil {
+		return err
+	}
+
+	backoff := p.MinBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	for i := 0; i < p.MaxRetries && err != nil && retryable(err); i++ {
+		if ctx.Err() != nil {
+			return err
+		}
+
+		timer := time.NewTimer(backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+
+		err = request()
+	}
+
+	return err
+}
+
+// isTransient reports whether err is caused by network
+// or server failure, which may not happen again.
+func isTransient(err error) bool {
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError ||
+			statusErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// isDialError reports whether err is caused by failed
+// connection, so the request wasn't sent.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// notSentError is caused by failure before
+// the request was sent, e.g. in nonce store.
+type notSentError struct {
+	err error
+}
+
+func (e *notSentError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the cause.
+func (e *notSentError) Unwrap() error {
+	return e.err
+}
+
+// isPreSend reports whether err is caused
+// by failure before the request was sent.
+func isPreSend(err error) bool {
+	var notSent *notSentError
+	return errors.As(err, &notSent) || isDialError(err)
+}
@@ -0,0 +1,162 @@
+package wexapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestClient_Retry(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		status    int
+		call      func(cli *Client) error
+		wantCalls int32
+		wantErr   bool
+	}{
+		{
+			name:      "public retried",
+			failures:  2,
+			status:    http.StatusInternalServerError,
+			call:      func(cli *Client) error { _, err := cli.Info(); return err },
+			wantCalls: 3,
+		},
+		{
+			name:      "public retries exhausted",
+			failures:  5,
+			status:    http.StatusBadGateway,
+			call:      func(cli *Client) error { _, err := cli.Info(); return err },
+			wantCalls: 4,
+			wantErr:   true,
+		},
+		{
+			name:      "public client error not retried",
+			failures:  1,
+			status:    http.StatusNotFound,
+			call:      func(cli *Client) error { _, err := cli.Info(); return err },
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "read only trade retried",
+			failures:  1,
+			status:    http.StatusServiceUnavailable,
+			call:      func(cli *Client) error { _, err := cli.GetInfo(); return err },
+			wantCalls: 2,
+		},
+		{
+			name:     "trade not retried",
+			failures: 1,
+			status:   http.StatusServiceUnavailable,
+			call: func(cli *Client) error {
+				_, err := cli.Trade(NewPair("btc", "usd"), Buy, decimal.RequireFromString("100"), decimal.RequireFromString("1"))
+				return err
+			},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:     "withdraw not retried",
+			failures: 1,
+			status:   http.StatusGatewayTimeout,
+			call: func(cli *Client) error {
+				_, err := cli.WithdrawCoin("btc", "address", decimal.RequireFromString("1"))
+				return err
+			},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&calls, 1) <= int32(tt.failures) {
+					w.WriteHeader(tt.status)
+					return
+				}
+				if r.URL.Path == "/tapi" {
+					fmt.Fprint(w, getInfoResponse)
+					return
+				}
+				fmt.Fprint(w, infoResponse)
+			}))
+			defer server.Close()
+			httpClient := testingHTTPClient(server)
+
+			cli := NewClient("key", "secret",
+				SetHTTPClient(httpClient),
+				SetRetryPolicy(RetryPolicy{
+					MaxRetries: 3,
+					MinBackoff: time.Millisecond,
+					MaxBackoff: 2 * time.Millisecond,
+				}),
+			)
+
+			err := tt.call(cli)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("server calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestClient_RetryPreSend(t *testing.T) {
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, tradeResponse)
+	}))
+	defer server.Close()
+
+	var dials int32
+	transport := transportForTesting(server)
+	dial := transport.Dial
+	transport.Dial = func(network, addr string) (net.Conn, error) {
+		if atomic.AddInt32(&dials, 1) == 1 {
+			return nil, &net.OpError{Op: "dial", Net: network, Err: errors.New("connection refused")}
+		}
+		return dial(network, addr)
+	}
+
+	cli := NewClient("key", "secret",
+		SetHTTPClient(&http.Client{Transport: transport}),
+		SetRetryPolicy(RetryPolicy{MaxRetries: 1}),
+	)
+
+	if _, err := cli.Trade(NewPair("btc", "usd"), Buy, decimal.RequireFromString("100"), decimal.RequireFromString("1")); err != nil {
+		t.Fatalf("Client.Trade() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&dials); got != 2 {
+		t.Errorf("dials = %d, want 2", got)
+	}
+}
+
+func TestRetryPolicy_defaultBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 1}
+	failure := errors.New("failure")
+
+	var calls int
+	start := time.Now()
+	err := policy.do(context.Background(), func(error) bool { return true }, func() error {
+		calls++
+		return failure
+	})
+	elapsed := time.Since(start)
+
+	if err != failure || calls != 2 {
+		t.Fatalf("RetryPolicy.do() error = %v after %d calls, want %v after 2", err, calls, failure)
+	}
+	if elapsed < defaultRetryBackoff/2 {
+		t.Errorf("retried after %v, want at least %v", elapsed, defaultRetryBackoff/2)
+	}
+}
@@ -0,0 +1,314 @@
+package wexapi
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	trackerEventsBuffer = 128
+
+	// defaultPollInterval is used for non positive poll
+	// intervals, public api data is cached for 2 seconds.
+	defaultPollInterval = 2 * time.Second
+)
+
+// LevelEventType is a type of the order book level change.
+type LevelEventType uint8
+
+// Available level event types.
+const (
+	LevelAdded LevelEventType = iota + 1
+	LevelUpdated
+	LevelRemoved
+)
+
+func (t LevelEventType) String() string {
+	switch t {
+	case LevelAdded:
+		return "added"
+	case LevelUpdated:
+		return "updated"
+	case LevelRemoved:
+		return "removed"
+	}
+	return "unknown"
+}
+
+// LevelEvent describes change of the order book level.
+// Side is Sell for asks and Buy for bids, Amount is zero
+// for removed levels.
+type LevelEvent struct {
+	Pair   Pair
+	Side   Side
+	Type   LevelEventType
+	Rate   decimal.Decimal
+	Amount decimal.Decimal
+}
+
+// OrderBookTracker maintains order books of the pairs
+// and emits events on their levels changes.
+// Use NewOrderBookTracker to initialize one.
+type OrderBookTracker struct {
+	cli      *Client
+	pairs    []Pair
+	limit    int
+	interval time.Duration
+	events   chan LevelEvent
+
+	mu        sync.RWMutex
+	books     map[Pair]OrderBook
+	pairLocks map[Pair]*sync.Mutex
+	err       error
+}
+
+// NewOrderBookTracker returns tracker which polls Depth
+// of the pairs limited to limit orders every interval
+// when running. Non positive interval means 2 seconds.
+func NewOrderBookTracker(cli *Client, interval time.Duration, limit int, pairs ...Pair) *OrderBookTracker {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	return &OrderBookTracker{
+		cli:       cli,
+		pairs:     pairs,
+		limit:     limit,
+		interval:  interval,
+		events:    make(chan LevelEvent, trackerEventsBuffer),
+		books:     make(map[Pair]OrderBook),
+		pairLocks: make(map[Pair]*sync.Mutex),
+	}
+}
+
+// Events returns channel of the level events, tracker
+// waits for them to be consumed, so it must be read.
+func (t *OrderBookTracker) Events() <-chan LevelEvent {
+	return t.events
+}
+
+// Book returns copy of the current order book of the pair.
+func (t *OrderBookTracker) Book(pair Pair) (OrderBook, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	book, ok := t.books[pair]
+	if !ok {
+		return OrderBook{}, false
+	}
+
+	return OrderBook{
+		Asks: append([]Order(nil), book.Asks...),
+		Bids: append([]Order(nil), book.Bids...),
+	}, true
+}
+
+// Err returns error of the last poll.
+func (t *OrderBookTracker) Err() error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.err
+}
+
+// Run polls order books until ctx is done. Errors of the
+// polls don't stop it, the last one is returned by Err.
+func (t *OrderBookTracker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.poll(ctx); err != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *OrderBookTracker) poll(ctx context.Context) error {
+	books, err := t.cli.DepthsContext(ctx, t.limit, t.pairs...)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	t.mu.Lock()
+	t.err = err
+	t.mu.Unlock()
+
+	for _, pair := range t.pairs {
+		book, ok := books[pair]
+		if !ok {
+			continue
+		}
+		if updateErr := t.Update(ctx, pair, book); updateErr != nil {
+			return updateErr
+		}
+	}
+
+	return err
+}
+
+// Update replaces order book of the pair with the snapshot
+// from any source and emits events on changed levels.
+func (t *OrderBookTracker) Update(ctx context.Context, pair Pair, book OrderBook) error {
+	return t.change(ctx, pair, func(OrderBook) OrderBook {
+		return OrderBook{
+			Asks: append([]Order(nil), book.Asks...),
+			Bids: append([]Order(nil), book.Bids...),
+		}
+	})
+}
+
+// Apply changes levels of the pair order book by the delta
+// holding changed levels only, e.g. Depth of the PushDepth
+// events, levels with zero amount are removed. Events are
+// emitted on changed levels.
+func (t *OrderBookTracker) Apply(ctx context.Context, pair Pair, delta OrderBook) error {
+	return t.change(ctx, pair, func(old OrderBook) OrderBook {
+		return OrderBook{
+			Asks: applyLevels(Sell, old.Asks, delta.Asks),
+			Bids: applyLevels(Buy, old.Bids, delta.Bids),
+		}
+	})
+}
+
+// change stores the next order book of the pair and emits
+// events, changes of the pair are serialized, so events
+// come in the order of the stored books.
+func (t *OrderBookTracker) change(ctx context.Context, pair Pair, next func(old OrderBook) OrderBook) error {
+	lock := t.pairLock(pair)
+	lock.Lock()
+	defer lock.Unlock()
+
+	t.mu.Lock()
+	old := t.books[pair]
+	book := next(old)
+	t.books[pair] = book
+	t.mu.Unlock()
+
+	events := levelEvents(pair, Sell, old.Asks, book.Asks)
+	events = append(events, levelEvents(pair, Buy, old.Bids, book.Bids)...)
+
+	for _, event := range events {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case t.events <- event:
+		}
+	}
+
+	return nil
+}
+
+func (t *OrderBookTracker) pairLock(pair Pair) *sync.Mutex {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	lock, ok := t.pairLocks[pair]
+	if !ok {
+		lock = new(sync.Mutex)
+		t.pairLocks[pair] = lock
+	}
+	return lock
+}
+
+// applyLevels returns levels of the side changed by
+// the delta sorted from the best rate.
+func applyLevels(side Side, orders, delta []Order) []Order {
+	result := levels(orders)
+	for _, order := range delta {
+		if order.Amount.IsZero() {
+			delete(result, order.Rate.String())
+			continue
+		}
+		result[order.Rate.String()] = Order{Rate: order.Rate, Amount: order.Amount}
+	}
+
+	applied := make([]Order, 0, len(result))
+	for _, order := range result {
+		order.CalculateTotal()
+		applied = append(applied, order)
+	}
+	sort.Slice(applied, func(i, j int) bool {
+		if side == Buy {
+			return applied[i].Rate.GreaterThan(applied[j].Rate)
+		}
+		return applied[i].Rate.LessThan(applied[j].Rate)
+	})
+	return applied
+}
+
+// levelEvents returns events of the changes between old and
+// current levels of the side sorted from the best rate.
+func levelEvents(pair Pair, side Side, old, current []Order) []LevelEvent {
+	oldLevels := levels(old)
+	currentLevels := levels(current)
+
+	rates := make([]decimal.Decimal, 0, len(oldLevels)+len(currentLevels))
+	for _, order := range currentLevels {
+		rates = append(rates, order.Rate)
+	}
+	for key, order := range oldLevels {
+		if _, ok := currentLevels[key]; !ok {
+			rates = append(rates, order.Rate)
+		}
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		if side == Buy {
+			return rates[i].GreaterThan(rates[j])
+		}
+		return rates[i].LessThan(rates[j])
+	})
+
+	var events []LevelEvent
+	for _, rate := range rates {
+		key := rate.String()
+		oldOrder, inOld := oldLevels[key]
+		currentOrder, inCurrent := currentLevels[key]
+
+		event := LevelEvent{
+			Pair: pair,
+			Side: side,
+			Rate: rate,
+		}
+		switch {
+		case !inOld:
+			event.Type, event.Amount = LevelAdded, currentOrder.Amount
+		case !inCurrent:
+			event.Type, event.Amount = LevelRemoved, decimal.Zero
+		case !oldOrder.Amount.Equal(currentOrder.Amount):
+			event.Type, event.Amount = LevelUpdated, currentOrder.Amount
+		default:
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// levels returns orders by rate, amounts of the
+// orders with the same rate are summed up.
+func levels(orders []Order) map[string]Order {
+	result := make(map[string]Order, len(orders))
+	for _, order := range orders {
+		key := order.Rate.String()
+		level, ok := result[key]
+		if !ok {
+			result[key] = Order{Rate: order.Rate, Amount: order.Amount}
+			continue
+		}
+		level.Amount = level.Amount.Add(order.Amount)
+		result[key] = level
+	}
+	return result
+}
@@ -0,0 +1,191 @@
+package wexapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOrderBookTracker(t *testing.T) {
+	responses := []string{
+		`{"btc_usd":{"asks":[[101,1],[102,2]],"bids":[[99,1]]}}`,
+		`{"btc_usd":{"asks":[[101,0.5],[103,1]],"bids":[[99,1],[98,3]]}}`,
+	}
+
+	var mu sync.Mutex
+	var calls int
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		response := responses[len(responses)-1]
+		if calls < len(responses) {
+			response = responses[calls]
+		}
+		calls++
+		fmt.Fprint(w, response)
+	}))
+	defer server.Close()
+	httpClient := testingHTTPClient(server)
+
+	cli := NewClient("", "", SetHTTPClient(httpClient))
+	pair := NewPair("btc", "usd")
+	tracker := NewOrderBookTracker(cli, 10*time.Millisecond, 10, pair)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- tracker.Run(ctx)
+	}()
+
+	want := []string{
+		"sell added 101 1",
+		"sell added 102 2",
+		"buy added 99 1",
+		"sell updated 101 0.5",
+		"sell removed 102 0",
+		"sell added 103 1",
+		"buy added 98 3",
+	}
+	for i, w := range want {
+		select {
+		case event := <-tracker.Events():
+			got := fmt.Sprintf("%s %s %s %s", event.Side, event.Type, event.Rate, event.Amount)
+			if got != w || event.Pair != pair {
+				t.Errorf("event %d = %s %s, want %s %s", i, event.Pair, got, pair, w)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d not received, want %s", i, w)
+		}
+	}
+
+	book, ok := tracker.Book(pair)
+	if !ok || len(book.Asks) != 2 || len(book.Bids) != 2 {
+		t.Errorf("OrderBookTracker.Book() = %v, %v, want 2 asks and 2 bids", book, ok)
+	}
+	if _, ok := tracker.Book(NewPair("ltc", "usd")); ok {
+		t.Error("OrderBookTracker.Book() found untracked pair")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("OrderBookTracker.Run() error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OrderBookTracker.Run() didn't stop")
+	}
+	if err := tracker.Err(); err != nil {
+		t.Errorf("OrderBookTracker.Err() = %v", err)
+	}
+}
+
+func TestOrderBookTracker_Apply(t *testing.T) {
+	pair := NewPair("btc", "usd")
+	tracker := NewOrderBookTracker(nil, time.Second, 0, pair)
+	ctx := context.Background()
+
+	snapshot := OrderBook{
+		Asks: []Order{newTestOrder("101", "1"), newTestOrder("102", "2")},
+		Bids: []Order{newTestOrder("99", "1"), newTestOrder("98", "3")},
+	}
+	if err := tracker.Update(ctx, pair, snapshot); err != nil {
+		t.Fatalf("OrderBookTracker.Update() error = %v", err)
+	}
+	for range make([]struct{}, 4) {
+		<-tracker.Events()
+	}
+
+	delta := OrderBook{
+		Asks: []Order{newTestOrder("100.5", "0.5"), newTestOrder("102", "0")},
+		Bids: []Order{newTestOrder("98", "1")},
+	}
+	if err := tracker.Apply(ctx, pair, delta); err != nil {
+		t.Fatalf("OrderBookTracker.Apply() error = %v", err)
+	}
+
+	var events []string
+	for range make([]struct{}, 3) {
+		event := <-tracker.Events()
+		events = append(events, fmt.Sprintf("%s %s %s %s", event.Side, event.Type, event.Rate, event.Amount))
+	}
+	wantEvents := []string{
+		"sell added 100.5 0.5",
+		"sell removed 102 0",
+		"buy updated 98 1",
+	}
+	if fmt.Sprint(events) != fmt.Sprint(wantEvents) {
+		t.Errorf("events = %q, want %q", events, wantEvents)
+	}
+
+	book, _ := tracker.Book(pair)
+	got := fmt.Sprintf("%v %v", book.Asks, book.Bids)
+	want := fmt.Sprintf("%v %v",
+		[]Order{newTestOrder("100.5", "0.5"), newTestOrder("101", "1")},
+		[]Order{newTestOrder("99", "1"), newTestOrder("98", "1")},
+	)
+	if got != want {
+		t.Errorf("OrderBookTracker.Book() = %s, want %s", got, want)
+	}
+}
+
+func TestOrderBookTracker_concurrentUpdates(t *testing.T) {
+	pair := NewPair("btc", "usd")
+	tracker := NewOrderBookTracker(nil, time.Second, 0, pair)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Replays events to the book, which must end up
+	// equal to the tracked one.
+	replayed := make(map[string]string)
+	consumed := make(chan struct{})
+	go func() {
+		defer close(consumed)
+		for event := range tracker.Events() {
+			if event.Type == LevelRemoved {
+				delete(replayed, event.Rate.String())
+				continue
+			}
+			replayed[event.Rate.String()] = event.Amount.String()
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				amount := fmt.Sprint(i*100 + j + 1)
+				book := OrderBook{Asks: []Order{newTestOrder(fmt.Sprint(100+j%3), amount)}}
+				if j%2 == 0 {
+					tracker.Update(ctx, pair, book)
+					continue
+				}
+				tracker.Apply(ctx, pair, book)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(tracker.events)
+	<-consumed
+
+	book, _ := tracker.Book(pair)
+	tracked := make(map[string]string)
+	for _, order := range book.Asks {
+		tracked[order.Rate.String()] = order.Amount.String()
+	}
+	if fmt.Sprint(replayed) != fmt.Sprint(tracked) {
+		t.Errorf("replayed book = %v, want %v", replayed, tracked)
+	}
+}
+
+func TestNewOrderBookTracker_defaultInterval(t *testing.T) {
+	tracker := NewOrderBookTracker(nil, 0, 10, NewPair("btc", "usd"))
+	if tracker.interval != defaultPollInterval {
+		t.Errorf("OrderBookTracker interval = %v, want %v", tracker.interval, defaultPollInterval)
+	}
+}
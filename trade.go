@@ -2,14 +2,18 @@ package wexapi
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha512"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
@@ -49,8 +53,13 @@ type UserInfo struct {
 // and Server Time.
 // To use this method you need a privilege of the key info.
 func (cli *Client) GetInfo() (UserInfo, error) {
+	return cli.GetInfoContext(context.Background())
+}
+
+// GetInfoContext is like GetInfo but uses ctx for the request.
+func (cli *Client) GetInfoContext(ctx context.Context) (UserInfo, error) {
 	userInfo := UserInfo{}
-	err := cli.tradeRequest(&userInfo, "getInfo")
+	err := cli.tradeRequest(ctx, &userInfo, "getInfo")
 	return userInfo, err
 }
 
@@ -65,23 +74,49 @@ type UserTrade struct {
 // Trade is the basic method that can be used for
 // creating orders and trading on the exchange.
 // To use this method you need a privilege of the key info.
-func (cli *Client) Trade(pair, tradeType string, rate, amount decimal.Decimal) (UserTrade, error) {
+func (cli *Client) Trade(pair Pair, side Side, rate, amount decimal.Decimal) (UserTrade, error) {
+	return cli.TradeContext(context.Background(), pair, side, rate, amount)
+}
+
+// TradeContext is like Trade but uses ctx for the request.
+func (cli *Client) TradeContext(ctx context.Context, pair Pair, side Side, rate, amount decimal.Decimal) (UserTrade, error) {
+	if err := pair.Validate(); err != nil {
+		return UserTrade{}, err
+	}
+	if err := side.Validate(); err != nil {
+		return UserTrade{}, err
+	}
+
+	if cli.orderValidator != nil {
+		var err error
+		rate, amount, err = cli.orderValidator.validate(ctx, pair, side, rate, amount)
+		if err != nil {
+			return UserTrade{}, err
+		}
+	}
+
+	return cli.trade(ctx, pair, side, rate, amount)
+}
+
+// trade places validated order.
+func (cli *Client) trade(ctx context.Context, pair Pair, side Side, rate, amount decimal.Decimal) (UserTrade, error) {
 	userTrade := UserTrade{}
-	params := []param{
-		param{key: "pair", value: pair},
-		param{key: "type", value: tradeType},
-		param{key: "rate", value: rate.String()},
-		param{key: "amount", value: amount.String()},
+	params := []Param{
+		Param{Key: "pair", Value: pair.String()},
+		Param{Key: "type", Value: side.String()},
+		Param{Key: "rate", Value: rate.String()},
+		Param{Key: "amount", Value: amount.String()},
 	}
-	err := cli.tradeRequest(&userTrade, "Trade", params...)
+	err := cli.tradeRequest(ctx, &userTrade, "Trade", params...)
+	cli.seeOrder(userTrade.OrderID)
 	return userTrade, err
 }
 
 // TradeOrder holds information about user trade orders.
 type TradeOrder struct {
 	ID               uint64
-	Pair             string          `json:"pair"`
-	Type             string          `json:"type"`
+	Pair             Pair            `json:"pair"`
+	Type             Side            `json:"type"`
 	StartAmount      decimal.Decimal `json:"start_amount"`
 	Amount           decimal.Decimal `json:"amount"`
 	Rate             decimal.Decimal `json:"rate"`
@@ -119,19 +154,36 @@ func (to *TradeOrders) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// ActiveOrders returns the list of your active orders.
+// ActiveOrders returns the list of your active orders,
+// orders of all pairs are returned if pair is zero.
 // To use this method you need a privilege of the info key.
-func (cli *Client) ActiveOrders(pair string) (TradeOrders, error) {
+func (cli *Client) ActiveOrders(pair Pair) (TradeOrders, error) {
+	return cli.ActiveOrdersContext(context.Background(), pair)
+}
+
+// ActiveOrdersContext is like ActiveOrders but uses ctx for the request.
+func (cli *Client) ActiveOrdersContext(ctx context.Context, pair Pair) (TradeOrders, error) {
+	var params []Param
+	if !pair.IsZero() {
+		if err := pair.Validate(); err != nil {
+			return TradeOrders{}, err
+		}
+		params = append(params, Param{Key: "pair", Value: pair.String()})
+	}
+
 	tradeOrders := TradeOrders{}
-	err := cli.tradeRequest(&tradeOrders, "ActiveOrders", param{key: "pair", value: pair})
+	err := cli.tradeRequest(ctx, &tradeOrders, "ActiveOrders", params...)
+	for _, order := range tradeOrders {
+		cli.seeOrder(order.ID)
+	}
 	return tradeOrders, err
 }
 
 // OrderInfo holds data about order
 type OrderInfo struct {
 	ID               uint64
-	Pair             string          `json:"pair"`
-	Type             string          `json:"type"`
+	Pair             Pair            `json:"pair"`
+	Type             Side            `json:"type"`
 	StartAmount      decimal.Decimal `json:"start_amount"`
 	Amount           decimal.Decimal `json:"amount"`
 	Rate             decimal.Decimal `json:"rate"`
@@ -142,9 +194,14 @@ type OrderInfo struct {
 // OrderInfo returns the information on particular order.
 // To use this method you need a privilege of the info key.
 func (cli *Client) OrderInfo(orderID uint64) (OrderInfo, error) {
+	return cli.OrderInfoContext(context.Background(), orderID)
+}
+
+// OrderInfoContext is like OrderInfo but uses ctx for the request.
+func (cli *Client) OrderInfoContext(ctx context.Context, orderID uint64) (OrderInfo, error) {
 	ordersInfo := make(map[string]OrderInfo)
 	orderIDString := strconv.FormatUint(orderID, 10)
-	err := cli.tradeRequest(&ordersInfo, "OrderInfo", param{key: "order_id", value: orderIDString})
+	err := cli.tradeRequest(ctx, &ordersInfo, "OrderInfo", Param{Key: "order_id", Value: orderIDString})
 	orderInfo := ordersInfo[orderIDString]
 	orderInfo.ID = orderID
 	return ordersInfo[orderIDString], err
@@ -158,8 +215,13 @@ type CancelOrder struct {
 // CancelOrder returns the information on particular order.
 // To use this method you need a privilege of the info key.
 func (cli *Client) CancelOrder(orderID uint64) (CancelOrder, error) {
+	return cli.CancelOrderContext(context.Background(), orderID)
+}
+
+// CancelOrderContext is like CancelOrder but uses ctx for the request.
+func (cli *Client) CancelOrderContext(ctx context.Context, orderID uint64) (CancelOrder, error) {
 	cancelOrder := CancelOrder{}
-	err := cli.tradeRequest(&cancelOrder, "CancelOrder", param{key: "order_id", value: strconv.FormatUint(orderID, 10)})
+	err := cli.tradeRequest(ctx, &cancelOrder, "CancelOrder", Param{Key: "order_id", Value: strconv.FormatUint(orderID, 10)})
 	return cancelOrder, err
 }
 
@@ -173,40 +235,471 @@ type Withdraw struct {
 // WithdrawCoin is designed for cryptocurrency withdrawals.
 // To use this method you need a privilege of the info key.
 func (cli *Client) WithdrawCoin(currency, address string, amount decimal.Decimal) (Withdraw, error) {
+	return cli.WithdrawCoinContext(context.Background(), currency, address, amount)
+}
+
+// WithdrawCoinContext is like WithdrawCoin but uses ctx for the request.
+func (cli *Client) WithdrawCoinContext(ctx context.Context, currency, address string, amount decimal.Decimal) (Withdraw, error) {
 	withdraw := Withdraw{}
-	params := []param{
-		param{key: "coinName", value: currency},
-		param{key: "address", value: address},
-		param{key: "amount", value: amount.String()},
+	params := []Param{
+		Param{Key: "coinName", Value: currency},
+		Param{Key: "address", Value: address, Sensitive: true},
+		Param{Key: "amount", Value: amount.String()},
 	}
-	err := cli.tradeRequest(&withdraw, "WithdrawCoin", params...)
+	err := cli.tradeRequest(ctx, &withdraw, "WithdrawCoin", params...)
 	return withdraw, err
 }
 
-func (cli *Client) tradeRequest(result interface{}, method string, params ...param) error {
+// DepositAddress holds data about deposit address.
+type DepositAddress struct {
+	Address string `json:"address"`
+}
+
+// CoinDepositAddress returns the deposit address
+// for the cryptocurrency.
+// To use this method you need a privilege of the info key.
+func (cli *Client) CoinDepositAddress(currency string) (DepositAddress, error) {
+	return cli.CoinDepositAddressContext(context.Background(), currency)
+}
+
+// CoinDepositAddressContext is like CoinDepositAddress but uses ctx for the request.
+func (cli *Client) CoinDepositAddressContext(ctx context.Context, currency string) (DepositAddress, error) {
+	depositAddress := DepositAddress{}
+	err := cli.tradeRequest(ctx, &depositAddress, "CoinDepositAddress", Param{Key: "coinName", Value: currency})
+	return depositAddress, err
+}
+
+const redacted = "[REDACTED]"
+
+// CouponCode is a code of WEX-code (coupon). It hides
+// itself when formatted, so it doesn't get into logs by
+// accident, use string(code) to get the actual code.
+type CouponCode string
+
+func (code CouponCode) String() string {
+	return redacted
+}
+
+// GoString implements fmt.GoStringer.
+func (code CouponCode) GoString() string {
+	return redacted
+}
+
+// Coupon holds data about created coupon.
+type Coupon struct {
+	Code          CouponCode `json:"coupon"`
+	TransactionID uint64     `json:"transID"`
+	Funds         Funds      `json:"funds"`
+}
+
+// CreateCoupon creates WEX-code (coupon) for the amount
+// of currency, receiver is a user who is allowed to redeem
+// it, anyone can redeem it if empty.
+// To use this method you need a privilege of the coupon key.
+func (cli *Client) CreateCoupon(currency string, amount decimal.Decimal, receiver string) (Coupon, error) {
+	return cli.CreateCouponContext(context.Background(), currency, amount, receiver)
+}
+
+// CreateCouponContext is like CreateCoupon but uses ctx for the request.
+func (cli *Client) CreateCouponContext(ctx context.Context, currency string, amount decimal.Decimal, receiver string) (Coupon, error) {
+	coupon := Coupon{}
+	params := []Param{
+		Param{Key: "currency", Value: currency},
+		Param{Key: "amount", Value: amount.String()},
+	}
+	if receiver != "" {
+		params = append(params, Param{Key: "receiver", Value: receiver})
+	}
+	err := cli.tradeRequest(ctx, &coupon, "CreateCoupon", params...)
+	return coupon, err
+}
+
+// RedeemedCoupon holds data about redeemed coupon.
+type RedeemedCoupon struct {
+	Amount        decimal.Decimal `json:"couponAmount"`
+	Currency      string          `json:"couponCurrency"`
+	TransactionID uint64          `json:"transID"`
+	Funds         Funds           `json:"funds"`
+}
+
+// RedeemCoupon redeems WEX-code (coupon).
+// To use this method you need a privilege of the coupon key.
+func (cli *Client) RedeemCoupon(code CouponCode) (RedeemedCoupon, error) {
+	return cli.RedeemCouponContext(context.Background(), code)
+}
+
+// RedeemCouponContext is like RedeemCoupon but uses ctx for the request.
+func (cli *Client) RedeemCouponContext(ctx context.Context, code CouponCode) (RedeemedCoupon, error) {
+	redeemedCoupon := RedeemedCoupon{}
+	err := cli.tradeRequest(ctx, &redeemedCoupon, "RedeemCoupon", Param{Key: "coupon", Value: string(code), Sensitive: true})
+	return redeemedCoupon, err
+}
+
+// SortOrder is a sorting order of the history requests.
+type SortOrder string
+
+// Available sort orders.
+const (
+	SortAsc  SortOrder = "ASC"
+	SortDesc SortOrder = "DESC"
+)
+
+// HistoryFilter holds filters for the history requests,
+// zero values aren't sent, so server defaults are used.
+type HistoryFilter struct {
+	From   uint64    // number of the record to start from
+	Count  uint64    // number of records to return
+	FromID uint64    // ID of the record to start from
+	EndID  uint64    // ID of the record to finish with
+	Order  SortOrder // sorting order
+	Since  time.Time // time to start from
+	End    time.Time // time to finish with
+}
+
+func (filter HistoryFilter) params() []Param {
+	params := make([]Param, 0)
+	addUint := func(key string, value uint64) {
+		if value != 0 {
+			params = append(params, Param{Key: key, Value: strconv.FormatUint(value, 10)})
+		}
+	}
+	addTime := func(key string, value time.Time) {
+		if !value.IsZero() {
+			params = append(params, Param{Key: key, Value: strconv.FormatInt(value.Unix(), 10)})
+		}
+	}
+
+	addUint("from", filter.From)
+	addUint("count", filter.Count)
+	addUint("from_id", filter.FromID)
+	addUint("end_id", filter.EndID)
+	if filter.Order != "" {
+		params = append(params, Param{Key: "order", Value: string(filter.Order)})
+	}
+	addTime("since", filter.Since)
+	addTime("end", filter.End)
+
+	return params
+}
+
+// TradeHistoryFilter holds filters for TradeHistory.
+type TradeHistoryFilter struct {
+	HistoryFilter
+	Pair Pair // pair to show trades for, all pairs if zero
+}
+
+func (filter TradeHistoryFilter) params() []Param {
+	params := filter.HistoryFilter.params()
+	if !filter.Pair.IsZero() {
+		params = append(params, Param{Key: "pair", Value: filter.Pair.String()})
+	}
+	return params
+}
+
+// HistoryTrade holds data about user trade from the history.
+type HistoryTrade struct {
+	ID          uint64
+	Pair        Pair            `json:"pair"`
+	Type        Side            `json:"type"`
+	Amount      decimal.Decimal `json:"amount"`
+	Rate        decimal.Decimal `json:"rate"`
+	OrderID     uint64          `json:"order_id"`
+	IsYourOrder convertibleBool `json:"is_your_order"`
+	Timestamp   unixTimestamp   `json:"timestamp"`
+}
+
+// HistoryTrades holds list of history trades.
+type HistoryTrades []HistoryTrade
+
+// UnmarshalJSON unmarshall map[string]HistoryTrade
+// format into the slice.
+func (ht *HistoryTrades) UnmarshalJSON(data []byte) error {
+	idRaw := make(map[string]json.RawMessage)
+
+	if err := json.Unmarshal(data, &idRaw); err != nil {
+		return errors.Wrap(err, "unmarshal to id raw")
+	}
+
+	for idString, data := range idRaw {
+		id, err := strconv.ParseUint(idString, 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "parse id %s", idString)
+		}
+
+		trade := HistoryTrade{
+			ID: id,
+		}
+		if err := json.Unmarshal(data, &trade); err != nil {
+			return errors.Wrapf(err, "unmarshal history trade %s", idString)
+		}
+
+		*ht = append(*ht, trade)
+	}
+
+	return nil
+}
+
+// TradeHistory returns trade history sorted by
+// the filter order, descending by default.
+// To use this method you need a privilege of the info key.
+func (cli *Client) TradeHistory(filter TradeHistoryFilter) (HistoryTrades, error) {
+	return cli.TradeHistoryContext(context.Background(), filter)
+}
+
+// TradeHistoryContext is like TradeHistory but uses ctx for the request.
+func (cli *Client) TradeHistoryContext(ctx context.Context, filter TradeHistoryFilter) (HistoryTrades, error) {
+	if !filter.Pair.IsZero() {
+		if err := filter.Pair.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	historyTrades := HistoryTrades{}
+	err := cli.tradeRequest(ctx, &historyTrades, "TradeHistory", filter.params()...)
+	for _, trade := range historyTrades {
+		cli.seeOrder(trade.OrderID)
+	}
+	sort.Slice(historyTrades, func(i, j int) bool {
+		if filter.Order == SortAsc {
+			return historyTrades[i].ID < historyTrades[j].ID
+		}
+		return historyTrades[i].ID > historyTrades[j].ID
+	})
+	return historyTrades, err
+}
+
+// TransactionType is a type of the account transaction.
+type TransactionType uint8
+
+// Available transaction types.
+const (
+	TransactionDeposit    TransactionType = 1
+	TransactionWithdrawal TransactionType = 2
+	TransactionCredit     TransactionType = 4
+	TransactionDebit      TransactionType = 5
+)
+
+func (tt TransactionType) String() string {
+	switch tt {
+	case TransactionDeposit:
+		return "deposit"
+	case TransactionWithdrawal:
+		return "withdrawal"
+	case TransactionCredit:
+		return "credit"
+	case TransactionDebit:
+		return "debit"
+	}
+	return fmt.Sprintf("transaction type %d", uint8(tt))
+}
+
+// TransactionStatus is a status of the account transaction.
+type TransactionStatus uint8
+
+// Available transaction statuses.
+const (
+	TransactionStatusCancelled    TransactionStatus = 0 // cancelled or failed
+	TransactionStatusPending      TransactionStatus = 1 // waiting for acceptance
+	TransactionStatusSuccessful   TransactionStatus = 2
+	TransactionStatusNotConfirmed TransactionStatus = 3
+)
+
+func (ts TransactionStatus) String() string {
+	switch ts {
+	case TransactionStatusCancelled:
+		return "cancelled"
+	case TransactionStatusPending:
+		return "pending"
+	case TransactionStatusSuccessful:
+		return "successful"
+	case TransactionStatusNotConfirmed:
+		return "not confirmed"
+	}
+	return fmt.Sprintf("transaction status %d", uint8(ts))
+}
+
+// Transaction holds data about deposit, withdrawal
+// or other account transaction.
+type Transaction struct {
+	ID          uint64
+	Type        TransactionType   `json:"type"`
+	Amount      decimal.Decimal   `json:"amount"`
+	Currency    string            `json:"currency"`
+	Description string            `json:"desc"`
+	Status      TransactionStatus `json:"status"`
+	Timestamp   unixTimestamp     `json:"timestamp"`
+}
+
+// Transactions holds list of transactions.
+type Transactions []Transaction
+
+// UnmarshalJSON unmarshall map[string]Transaction
+// format into the slice.
+func (ts *Transactions) UnmarshalJSON(data []byte) error {
+	idRaw := make(map[string]json.RawMessage)
+
+	if err := json.Unmarshal(data, &idRaw); err != nil {
+		return errors.Wrap(err, "unmarshal to id raw")
+	}
+
+	for idString, data := range idRaw {
+		id, err := strconv.ParseUint(idString, 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "parse id %s", idString)
+		}
+
+		transaction := Transaction{
+			ID: id,
+		}
+		if err := json.Unmarshal(data, &transaction); err != nil {
+			return errors.Wrapf(err, "unmarshal transaction %s", idString)
+		}
+
+		*ts = append(*ts, transaction)
+	}
+
+	return nil
+}
+
+// TransHistory returns the history of transactions sorted
+// by the filter order, descending by default.
+// To use this method you need a privilege of the info key.
+func (cli *Client) TransHistory(filter HistoryFilter) (Transactions, error) {
+	return cli.TransHistoryContext(context.Background(), filter)
+}
+
+// TransHistoryContext is like TransHistory but uses ctx for the request.
+func (cli *Client) TransHistoryContext(ctx context.Context, filter HistoryFilter) (Transactions, error) {
+	transactions := Transactions{}
+	err := cli.tradeRequest(ctx, &transactions, "TransHistory", filter.params()...)
+	sort.Slice(transactions, func(i, j int) bool {
+		if filter.Order == SortAsc {
+			return transactions[i].ID < transactions[j].ID
+		}
+		return transactions[i].ID > transactions[j].ID
+	})
+	return transactions, err
+}
+
+func (cli *Client) tradeRequest(ctx context.Context, result interface{}, method string, params ...Param) error {
+	err := cli.retriedRequest(ctx, result, method, params...)
+
+	// The nonce could be used by another client with the same key,
+	// in that case server tells the last one it got, so retry once
+	// with a nonce following it.
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Code == ErrInvalidNonce {
+		if next, ok := expectedNonce(apiErr.Message); ok {
+			if err := cli.nonceStore.Sync(next); err != nil {
+				return &notSentError{err: errors.Wrap(err, "nonce sync")}
+			}
+			return cli.retriedRequest(ctx, result, method, params...)
+		}
+	}
+
+	return err
+}
+
+func (cli *Client) retriedRequest(ctx context.Context, result interface{}, method string, params ...Param) error {
+	retryable := isDialError
+	if idempotentTradeMethods[method] {
+		retryable = isTransient
+	}
+
+	return cli.retryPolicy.do(ctx, retryable, func() error {
+		// Waits before nonce is taken, so the nonce isn't held
+		// while waiting. Concurrent requests can still reach the
+		// server out of order of their nonces, the late ones fail
+		// with invalid nonce error and are retried once.
+		if err := cli.tradeLimiter.wait(ctx); err != nil {
+			return &notSentError{err: errors.Wrap(err, "rate limit")}
+		}
+
+		req := &Request{
+			API:    TradeAPI,
+			Method: method,
+			Params: append([]Param(nil), params...),
+		}
+		send := cli.sendTrade
+		var original json.RawMessage
+		if fields := sensitiveResultFields[method]; len(fields) > 0 {
+			send = redactResult(send, &original, fields)
+		}
+
+		resp, err := cli.handle(ctx, req, send)
+		if err != nil {
+			return err
+		}
+
+		// Middlewares get the redacted result,
+		// the original one is decoded.
+		if original != nil {
+			resp.Result = original
+		}
+		err = json.Unmarshal(resp.Result, result)
+		return errors.Wrap(err, "unmarshal to result")
+	})
+}
+
+// sensitiveResultFields are fields of the trade api results
+// which must not be exposed to middlewares, e.g. logged.
+var sensitiveResultFields = map[string][]string{
+	"CreateCoupon": {"coupon"},
+}
+
+// redactResult wraps send to replace fields of the result with
+// redacted value, the original result is stored into original.
+func redactResult(send Handler, original *json.RawMessage, fields []string) Handler {
+	return func(ctx context.Context, req *Request) (*Response, error) {
+		resp, err := send(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+
+		result := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(resp.Result, &result); err != nil {
+			return nil, errors.Wrap(err, "unmarshal to result")
+		}
+		for _, field := range fields {
+			if _, ok := result[field]; ok {
+				result[field] = json.RawMessage(strconv.Quote(redacted))
+			}
+		}
+		redactedResult, err := json.Marshal(result)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal redacted result")
+		}
+
+		*original = resp.Result
+		return &Response{StatusCode: resp.StatusCode, Result: redactedResult}, nil
+	}
+}
+
+func (cli *Client) sendTrade(ctx context.Context, r *Request) (*Response, error) {
 	nonce, err := cli.nonce()
 	if err != nil {
-		return errors.Wrap(err, "nonce")
+		return nil, &notSentError{err: errors.Wrap(err, "nonce")}
 	}
 
 	data := url.Values{
-		"method": []string{method},
+		"method": []string{r.Method},
 		"nonce":  []string{nonce},
 	}
 
-	for _, param := range params {
-		data.Add(param.key, param.value)
+	for _, param := range r.Params {
+		data.Add(param.Key, param.Value)
 	}
 
 	buf := bytes.NewBufferString(data.Encode())
-	req, err := http.NewRequest("POST", tradeAPIEndpoint, buf)
+	req, err := http.NewRequest("POST", cli.tradeEndpoint, buf)
 	if err != nil {
-		return errors.Wrap(err, "request build")
+		return nil, &notSentError{err: errors.Wrap(err, "request build")}
 	}
+	req = req.WithContext(ctx)
 
 	sign := hmac.New(sha512.New, []byte(cli.secret))
 	if _, err := sign.Write(buf.Bytes()); err != nil {
-		return errors.Wrap(err, "hmac write signature")
+		return nil, &notSentError{err: errors.Wrap(err, "hmac write signature")}
 	}
 
 	req.Header.Set("Key", cli.key)
@@ -215,28 +708,27 @@ func (cli *Client) tradeRequest(result interface{}, method string, params ...par
 
 	resp, err := cli.httpClient.Do(req)
 	if err != nil {
-		return errors.Wrap(err, "do request")
+		return nil, errors.Wrap(err, "do request")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.Errorf("server respond with status code %d", resp.StatusCode)
+		return nil, &statusError{StatusCode: resp.StatusCode}
 	}
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return errors.Wrap(err, "read response body")
+		return nil, errors.Wrap(err, "read response body")
 	}
 
 	br := baseResponse{}
 	if err := json.Unmarshal(body, &br); err != nil {
-		return errors.Wrap(err, "unmarshal to base response")
+		return nil, errors.Wrap(err, "unmarshal to base response")
 	}
 
 	if !br.Success && br.Error != nil {
-		return errors.Errorf("server respond with error: %s", *br.Error)
+		return nil, newAPIError(r.Method, *br.Error, resp.StatusCode)
 	}
 
-	err = json.Unmarshal(br.Return, result)
-	return errors.Wrap(err, "unmarshal to result")
+	return &Response{StatusCode: resp.StatusCode, Result: br.Return}, nil
 }
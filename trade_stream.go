@@ -0,0 +1,164 @@
+package wexapi
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	tradeStreamEventsBuffer = 128
+
+	// defaultTradesLimit is a number of trades
+	// returned by the server if limit isn't set.
+	defaultTradesLimit = 150
+)
+
+// TradeStreamEvent holds new trades of the pair sorted
+// by ID. Gap is set if all the polled trades were new,
+// so some trades between polls could be missed.
+type TradeStreamEvent struct {
+	Pair   Pair
+	Trades []Trade
+	Gap    bool
+}
+
+// TradeStream polls Trades of the pairs and
+// emits only trades which weren't seen before.
+// Use NewTradeStream to initialize one.
+type TradeStream struct {
+	cli      *Client
+	pairs    []Pair
+	limit    int
+	interval time.Duration
+	events   chan TradeStreamEvent
+
+	mu       sync.RWMutex
+	lastSeen map[Pair]uint64
+	err      error
+}
+
+// NewTradeStream returns stream which polls limit last
+// trades of the pairs every interval when running. Non
+// positive limit means the server default of 150 trades,
+// non positive interval means 2 seconds.
+func NewTradeStream(cli *Client, interval time.Duration, limit int, pairs ...Pair) *TradeStream {
+	if limit <= 0 {
+		limit = defaultTradesLimit
+	}
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	return &TradeStream{
+		cli:      cli,
+		pairs:    pairs,
+		limit:    limit,
+		interval: interval,
+		events:   make(chan TradeStreamEvent, tradeStreamEventsBuffer),
+		lastSeen: make(map[Pair]uint64),
+	}
+}
+
+// Events returns channel of the new trades, stream
+// waits for them to be consumed, so it must be read.
+func (s *TradeStream) Events() <-chan TradeStreamEvent {
+	return s.events
+}
+
+// LastSeen returns ID of the last seen trade of the pair.
+func (s *TradeStream) LastSeen(pair Pair) uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.lastSeen[pair]
+}
+
+// Err returns error of the last poll.
+func (s *TradeStream) Err() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.err
+}
+
+// Run polls trades until ctx is done. Errors of the
+// polls don't stop it, the last one is returned by Err.
+func (s *TradeStream) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.poll(ctx); err != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *TradeStream) poll(ctx context.Context) error {
+	trades, err := s.cli.TradesMultiContext(ctx, s.limit, s.pairs...)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+
+	for _, pair := range s.pairs {
+		polled, ok := trades[pair]
+		if !ok {
+			continue
+		}
+
+		event, ok := s.newTrades(pair, polled)
+		if !ok {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case s.events <- event:
+		}
+	}
+
+	return err
+}
+
+// newTrades filters out seen trades and moves
+// the last seen ID of the pair forward.
+func (s *TradeStream) newTrades(pair Pair, polled []Trade) (TradeStreamEvent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lastSeen, seenBefore := s.lastSeen[pair]
+
+	fresh := make([]Trade, 0, len(polled))
+	for _, trade := range polled {
+		if trade.ID > lastSeen {
+			fresh = append(fresh, trade)
+		}
+	}
+	if len(fresh) == 0 {
+		return TradeStreamEvent{}, false
+	}
+
+	sort.Slice(fresh, func(i, j int) bool {
+		return fresh[i].ID < fresh[j].ID
+	})
+	s.lastSeen[pair] = fresh[len(fresh)-1].ID
+
+	return TradeStreamEvent{
+		Pair:   pair,
+		Trades: fresh,
+		Gap:    seenBefore && len(fresh) == len(polled) && len(polled) >= s.limit,
+	}, true
+}
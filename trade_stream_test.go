@@ -0,0 +1,99 @@
+package wexapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTradeStream(t *testing.T) {
+	responses := []string{
+		`{"btc_usd":[{"type":"ask","price":100,"amount":1,"tid":2,"timestamp":1},{"type":"bid","price":101,"amount":1,"tid":1,"timestamp":1}]}`,
+		`{"btc_usd":[{"type":"ask","price":100,"amount":1,"tid":3,"timestamp":2},{"type":"ask","price":100,"amount":1,"tid":2,"timestamp":1}]}`,
+		`{"btc_usd":[{"type":"ask","price":100,"amount":1,"tid":3,"timestamp":2},{"type":"ask","price":100,"amount":1,"tid":2,"timestamp":1}]}`,
+		`{"btc_usd":[{"type":"bid","price":102,"amount":1,"tid":9,"timestamp":3},{"type":"bid","price":102,"amount":1,"tid":8,"timestamp":3}]}`,
+	}
+
+	var mu sync.Mutex
+	var calls int
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		response := responses[len(responses)-1]
+		if calls < len(responses) {
+			response = responses[calls]
+		}
+		calls++
+		fmt.Fprint(w, response)
+	}))
+	defer server.Close()
+	httpClient := testingHTTPClient(server)
+
+	cli := NewClient("", "", SetHTTPClient(httpClient))
+	pair := NewPair("btc", "usd")
+	stream := NewTradeStream(cli, 10*time.Millisecond, 2, pair)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- stream.Run(ctx)
+	}()
+
+	tests := []struct {
+		ids []uint64
+		gap bool
+	}{
+		{ids: []uint64{1, 2}, gap: false},
+		{ids: []uint64{3}, gap: false},
+		{ids: []uint64{8, 9}, gap: true},
+	}
+	for i, tt := range tests {
+		select {
+		case event := <-stream.Events():
+			var ids []uint64
+			for _, trade := range event.Trades {
+				ids = append(ids, trade.ID)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.ids) || event.Gap != tt.gap || event.Pair != pair {
+				t.Errorf("event %d = %s %v gap %v, want %s %v gap %v", i, event.Pair, ids, event.Gap, pair, tt.ids, tt.gap)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d not received", i)
+		}
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("TradeStream.Run() error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("TradeStream.Run() didn't stop")
+	}
+
+	if got := stream.LastSeen(pair); got != 9 {
+		t.Errorf("TradeStream.LastSeen() = %d, want 9", got)
+	}
+}
+
+func TestTradeStream_defaultLimit(t *testing.T) {
+	pair := NewPair("btc", "usd")
+	stream := NewTradeStream(nil, time.Second, 0, pair)
+
+	stream.newTrades(pair, []Trade{newTestTrade(1, 1, "100", "1")})
+	event, ok := stream.newTrades(pair, []Trade{newTestTrade(2, 2, "100", "1")})
+	if !ok || event.Gap {
+		t.Errorf("TradeStream.newTrades() = %v, %v, want event without gap", event, ok)
+	}
+}
+
+func TestNewTradeStream_defaultInterval(t *testing.T) {
+	stream := NewTradeStream(nil, 0, 10, NewPair("btc", "usd"))
+	if stream.interval != defaultPollInterval {
+		t.Errorf("TradeStream interval = %v, want %v", stream.interval, defaultPollInterval)
+	}
+}
@@ -1,6 +1,8 @@
 package wexapi
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"sort"
@@ -71,6 +73,87 @@ const (
 			}
 		}
 	}`
+	coinDepositAddressResponse = `{
+		"success":1,
+		"return":{
+			"address":"1UHAnAWvxDB9XXETsi7z483zRRBmcUZxb3"
+		}
+	}`
+	createCouponResponse = `{
+		"success":1,
+		"return":{
+			"coupon":"WEXUSD-0YT3HTDF-M0Y4DL1U-AS08CDYZ-TI2L54DH",
+			"transID":2186,
+			"funds":{
+				"usd":26,
+				"btc":100.1
+			}
+		}
+	}`
+	redeemCouponResponse = `{
+		"success":1,
+		"return":{
+			"couponAmount":"1",
+			"couponCurrency":"USD",
+			"transID":2186,
+			"funds":{
+				"usd":27,
+				"btc":100.1
+			}
+		}
+	}`
+	tradeHistoryResponse = `{
+		"success":1,
+		"return":{
+			"166830":{
+				"pair":"btc_usd",
+				"type":"sell",
+				"amount":1,
+				"rate":450,
+				"order_id":343148,
+				"is_your_order":1,
+				"timestamp":1342445793
+			},
+			"166831":{
+				"pair":"btc_usd",
+				"type":"buy",
+				"amount":0.5,
+				"rate":451,
+				"order_id":343150,
+				"is_your_order":0,
+				"timestamp":1342445800
+			}
+		}
+	}`
+	transHistoryResponse = `{
+		"success":1,
+		"return":{
+			"1081672":{
+				"type":1,
+				"amount":1.5,
+				"currency":"BTC",
+				"desc":"BTC Payment",
+				"status":2,
+				"timestamp":1342448420
+			},
+			"1081673":{
+				"type":2,
+				"amount":0.5,
+				"currency":"BTC",
+				"desc":"BTC Withdrawal",
+				"status":1,
+				"timestamp":1342448500
+			},
+			"1081674":{
+				"type":2,
+				"amount":0.25,
+				"currency":"BTC",
+				"desc":"BTC Withdrawal",
+				"status":0,
+				"timestamp":1342448600
+			}
+		}
+	}`
 	withdrawResponse = `{
 		"success":1,
 		"return":{
@@ -140,7 +223,7 @@ func TestClient_tradeRequest(t *testing.T) {
 
 			cli := NewClient("", "", SetHTTPClient(httpClient))
 
-			err := cli.tradeRequest(&baseResponse{}, "any")
+			err := cli.tradeRequest(context.Background(), &baseResponse{}, "any")
 
 			if tt.wantErr {
 				if err == nil {
@@ -253,7 +336,7 @@ func TestClient_Trade(t *testing.T) {
 			httpClient := testingHTTPClient(server)
 
 			cli := NewClient("", "", SetHTTPClient(httpClient))
-			got, err := cli.Trade("eth_btc", "sell", decimal.Zero, decimal.Zero)
+			got, err := cli.Trade(NewPair("eth", "btc"), Sell, decimal.Zero, decimal.Zero)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Client.Trade() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -280,8 +363,8 @@ func TestClient_ActiveOrders(t *testing.T) {
 			want: TradeOrders{
 				TradeOrder{
 					ID:               343152,
-					Pair:             "btc_usd",
-					Type:             "sell",
+					Pair:             NewPair("btc", "usd"),
+					Type:             Sell,
 					Amount:           decimal.NewFromFloatWithExponent(12.345, -3),
 					Rate:             decimal.NewFromFloatWithExponent(485, 0),
 					TimestampCreated: unixTimestamp(time.Unix(1342448420, 0)),
@@ -297,7 +380,7 @@ func TestClient_ActiveOrders(t *testing.T) {
 			httpClient := testingHTTPClient(server)
 
 			cli := NewClient("", "", SetHTTPClient(httpClient))
-			got, err := cli.ActiveOrders("btc_usd")
+			got, err := cli.ActiveOrders(NewPair("btc", "usd"))
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Client.ActiveOrders() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -322,8 +405,8 @@ func TestClient_OrderInfo(t *testing.T) {
 				fmt.Fprint(w, orderInfoResponse)
 			}),
 			want: OrderInfo{
-				Pair:             "btc_usd",
-				Type:             "sell",
+				Pair:             NewPair("btc", "usd"),
+				Type:             Sell,
 				StartAmount:      decimal.NewFromFloatWithExponent(13.345, -3),
 				Amount:           decimal.NewFromFloatWithExponent(12.345, -3),
 				Rate:             decimal.NewFromFloatWithExponent(485, 0),
@@ -352,6 +435,305 @@ func TestClient_OrderInfo(t *testing.T) {
 	}
 }
 
+func TestClient_CoinDepositAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.Handler
+		want    DepositAddress
+		wantErr bool
+	}{
+		{
+			name: "valid json",
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.FormValue("coinName") != "BTC" {
+					fmt.Fprint(w, invalidMethodResponse)
+					return
+				}
+				fmt.Fprint(w, coinDepositAddressResponse)
+			}),
+			want: DepositAddress{
+				Address: "1UHAnAWvxDB9XXETsi7z483zRRBmcUZxb3",
+			},
+			wantErr: false,
+		},
+		{
+			name: "error response",
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, invalidMethodResponse)
+			}),
+			want:    DepositAddress{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createFakeServer(tt.handler)
+			defer server.Close()
+			httpClient := testingHTTPClient(server)
+
+			cli := NewClient("", "", SetHTTPClient(httpClient))
+			got, err := cli.CoinDepositAddress("BTC")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.CoinDepositAddress() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Client.CoinDepositAddress() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_CreateCoupon(t *testing.T) {
+	var gotParams string
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotParams = fmt.Sprintf("%s %s %s", r.FormValue("currency"), r.FormValue("amount"), r.FormValue("receiver"))
+		fmt.Fprint(w, createCouponResponse)
+	}))
+	defer server.Close()
+	httpClient := testingHTTPClient(server)
+
+	cli := NewClient("", "", SetHTTPClient(httpClient))
+	got, err := cli.CreateCoupon("USD", decimal.New(1, 0), "receiver")
+	if err != nil {
+		t.Fatalf("Client.CreateCoupon() error = %v", err)
+	}
+
+	if gotParams != "USD 1 receiver" {
+		t.Errorf("sent params %q, want %q", gotParams, "USD 1 receiver")
+	}
+	want := Coupon{
+		Code:          "WEXUSD-0YT3HTDF-M0Y4DL1U-AS08CDYZ-TI2L54DH",
+		TransactionID: 2186,
+		Funds: Funds{
+			"usd": decimal.New(26, 0),
+			"btc": decimal.NewFromFloatWithExponent(100.1, -1),
+		},
+	}
+	if string(got.Code) != string(want.Code) || got.TransactionID != want.TransactionID || !compareAsStrings(got.Funds, want.Funds) {
+		t.Errorf("Client.CreateCoupon() = %#v, want %#v", got, want)
+	}
+	if formatted := fmt.Sprintf("%v %s %#v %+v", got.Code, got.Code, got.Code, got); strings.Contains(formatted, string(want.Code)) {
+		t.Errorf("formatted coupon exposes the code: %s", formatted)
+	}
+}
+
+func TestClient_RedeemCoupon(t *testing.T) {
+	var gotCoupon string
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotCoupon = r.FormValue("coupon")
+		fmt.Fprint(w, redeemCouponResponse)
+	}))
+	defer server.Close()
+	httpClient := testingHTTPClient(server)
+
+	cli := NewClient("", "", SetHTTPClient(httpClient))
+	got, err := cli.RedeemCoupon("WEXUSD-CODE")
+	if err != nil {
+		t.Fatalf("Client.RedeemCoupon() error = %v", err)
+	}
+
+	if gotCoupon != "WEXUSD-CODE" {
+		t.Errorf("sent coupon %s, want WEXUSD-CODE", gotCoupon)
+	}
+	want := RedeemedCoupon{
+		Amount:        decimal.New(1, 0),
+		Currency:      "USD",
+		TransactionID: 2186,
+		Funds: Funds{
+			"usd": decimal.New(27, 0),
+			"btc": decimal.NewFromFloatWithExponent(100.1, -1),
+		},
+	}
+	if !compareAsStrings(got, want) {
+		t.Errorf("Client.RedeemCoupon() = %v, want %v", got, want)
+	}
+}
+
+func TestClient_TradeHistory(t *testing.T) {
+	tests := []struct {
+		name       string
+		filter     TradeHistoryFilter
+		wantParams map[string]string
+		want       HistoryTrades
+		wantErr    bool
+	}{
+		{
+			name: "default order",
+			filter: TradeHistoryFilter{
+				Pair: NewPair("btc", "usd"),
+			},
+			wantParams: map[string]string{
+				"pair":  "btc_usd",
+				"order": "",
+				"since": "",
+			},
+			want: HistoryTrades{
+				HistoryTrade{
+					ID:          166831,
+					Pair:        NewPair("btc", "usd"),
+					Type:        Buy,
+					Amount:      decimal.NewFromFloatWithExponent(0.5, -1),
+					Rate:        decimal.NewFromFloatWithExponent(451, 0),
+					OrderID:     343150,
+					IsYourOrder: false,
+					Timestamp:   unixTimestamp(time.Unix(1342445800, 0)),
+				},
+				HistoryTrade{
+					ID:          166830,
+					Pair:        NewPair("btc", "usd"),
+					Type:        Sell,
+					Amount:      decimal.NewFromFloatWithExponent(1, 0),
+					Rate:        decimal.NewFromFloatWithExponent(450, 0),
+					OrderID:     343148,
+					IsYourOrder: true,
+					Timestamp:   unixTimestamp(time.Unix(1342445793, 0)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ascending with filters",
+			filter: TradeHistoryFilter{
+				HistoryFilter: HistoryFilter{
+					Count:  2,
+					FromID: 166830,
+					Order:  SortAsc,
+					Since:  time.Unix(1342445000, 0),
+				},
+			},
+			wantParams: map[string]string{
+				"pair":    "",
+				"count":   "2",
+				"from_id": "166830",
+				"order":   "ASC",
+				"since":   "1342445000",
+			},
+			want: HistoryTrades{
+				HistoryTrade{
+					ID:          166830,
+					Pair:        NewPair("btc", "usd"),
+					Type:        Sell,
+					Amount:      decimal.NewFromFloatWithExponent(1, 0),
+					Rate:        decimal.NewFromFloatWithExponent(450, 0),
+					OrderID:     343148,
+					IsYourOrder: true,
+					Timestamp:   unixTimestamp(time.Unix(1342445793, 0)),
+				},
+				HistoryTrade{
+					ID:          166831,
+					Pair:        NewPair("btc", "usd"),
+					Type:        Buy,
+					Amount:      decimal.NewFromFloatWithExponent(0.5, -1),
+					Rate:        decimal.NewFromFloatWithExponent(451, 0),
+					OrderID:     343150,
+					IsYourOrder: false,
+					Timestamp:   unixTimestamp(time.Unix(1342445800, 0)),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid pair",
+			filter: TradeHistoryFilter{
+				Pair: NewPair("BTC", ""),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotParams := make(map[string]string)
+			server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for key := range tt.wantParams {
+					gotParams[key] = r.FormValue(key)
+				}
+				fmt.Fprint(w, tradeHistoryResponse)
+			}))
+			defer server.Close()
+			httpClient := testingHTTPClient(server)
+
+			cli := NewClient("", "", SetHTTPClient(httpClient))
+			got, err := cli.TradeHistory(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.TradeHistory() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			for key, want := range tt.wantParams {
+				if gotParams[key] != want {
+					t.Errorf("param %s = %q, want %q", key, gotParams[key], want)
+				}
+			}
+			if !compareAsStrings(got, tt.want) {
+				t.Errorf("Client.TradeHistory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_TransHistory(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.Handler
+		want    Transactions
+		wantErr bool
+	}{
+		{
+			name: "valid json",
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, transHistoryResponse)
+			}),
+			want: Transactions{
+				Transaction{
+					ID:          1081674,
+					Type:        TransactionWithdrawal,
+					Amount:      decimal.NewFromFloatWithExponent(0.25, -2),
+					Currency:    "BTC",
+					Description: "BTC Withdrawal",
+					Status:      TransactionStatusCancelled,
+					Timestamp:   unixTimestamp(time.Unix(1342448600, 0)),
+				},
+				Transaction{
+					ID:          1081673,
+					Type:        TransactionWithdrawal,
+					Amount:      decimal.NewFromFloatWithExponent(0.5, -1),
+					Currency:    "BTC",
+					Description: "BTC Withdrawal",
+					Status:      TransactionStatusPending,
+					Timestamp:   unixTimestamp(time.Unix(1342448500, 0)),
+				},
+				Transaction{
+					ID:          1081672,
+					Type:        TransactionDeposit,
+					Amount:      decimal.NewFromFloatWithExponent(1.5, -1),
+					Currency:    "BTC",
+					Description: "BTC Payment",
+					Status:      TransactionStatusSuccessful,
+					Timestamp:   unixTimestamp(time.Unix(1342448420, 0)),
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createFakeServer(tt.handler)
+			defer server.Close()
+			httpClient := testingHTTPClient(server)
+
+			cli := NewClient("", "", SetHTTPClient(httpClient))
+			got, err := cli.TransHistory(HistoryFilter{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.TransHistory() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !compareAsStrings(got, tt.want) {
+				t.Errorf("Client.TransHistory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestClient_WithdrawCoin(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -395,6 +777,81 @@ func TestClient_WithdrawCoin(t *testing.T) {
 	}
 }
 
+func TestClient_GetInfoContext(t *testing.T) {
+	release := make(chan struct{})
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	httpClient := testingHTTPClient(server)
+
+	cli := NewClient("", "", SetHTTPClient(httpClient))
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := cli.GetInfoContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Client.GetInfoContext() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestClient_tradeRequestNonceResync(t *testing.T) {
+	tests := []struct {
+		name      string
+		errText   string
+		wantNonce string
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "resync",
+			errText:   "invalid nonce parameter; on key:1900000000, you sent:'1000', you should send:1900000001",
+			wantNonce: "1900000001",
+			wantCalls: 2,
+			wantErr:   false,
+		},
+		{
+			name:      "unparsable message",
+			errText:   "invalid nonce parameter",
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			var gotNonce string
+			server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				gotNonce = r.FormValue("nonce")
+				if calls == 1 {
+					fmt.Fprintf(w, `{"success":0,"error":"%s"}`, tt.errText)
+					return
+				}
+				fmt.Fprint(w, getInfoResponse)
+			}))
+			defer server.Close()
+			httpClient := testingHTTPClient(server)
+
+			cli := NewClient("", "", SetHTTPClient(httpClient))
+			_, err := cli.GetInfo()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.GetInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("server calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if !tt.wantErr && gotNonce != tt.wantNonce {
+				t.Errorf("retried with nonce %s, want %s", gotNonce, tt.wantNonce)
+			}
+		})
+	}
+}
+
 func BenchmarkClient_GetInfo(b *testing.B) {
 	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, getInfoResponse)
@@ -408,3 +865,19 @@ func BenchmarkClient_GetInfo(b *testing.B) {
 		cli.GetInfo()
 	}
 }
+
+func BenchmarkClient_TradeParallel(b *testing.B) {
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, tradeResponse)
+	}))
+	defer server.Close()
+	httpClient := testingHTTPClient(server)
+	cli := NewClient("", "", SetHTTPClient(httpClient))
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			cli.Trade(NewPair("btc", "usd"), Buy, decimal.Zero, decimal.Zero)
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package wexapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// amountDecimalPlaces is a precision of the order amount,
+// PairInfo.DecimalPlaces is applied to the rate only.
+const amountDecimalPlaces = 8
+
+// pairsInfoTTL is how long Info is cached by the validator,
+// so changes of the pair limits are picked up.
+const pairsInfoTTL = 5 * time.Minute
+
+// SetOrderValidation enables validation of the orders
+// against the pair limits from Info before they're signed
+// and sent. If round is true rate is rounded and amount is
+// truncated to the pair precision instead of being rejected,
+// see PairInfo.RoundOrder. Info is cached by the client for 5 minutes.
+func SetOrderValidation(round bool) Option {
+	return func(cli *Client) {
+		cli.orderValidator = &orderValidator{
+			cli:   cli,
+			round: round,
+			ttl:   pairsInfoTTL,
+		}
+	}
+}
+
+// ValidationError describes why the order was
+// rejected by the client side validation.
+type ValidationError struct {
+	Pair   Pair
+	Field  string
+	Value  decimal.Decimal
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("invalid order for %s: %s", e.Pair, e.Reason)
+	}
+	return fmt.Sprintf("invalid order for %s: %s %s %s", e.Pair, e.Field, e.Value, e.Reason)
+}
+
+// RoundOrder rounds rate and truncates amount to the precision
+// allowed for the pair. Buy rate is rounded down and sell rate
+// up, so the order is never worse than the given rate.
+func (info PairInfo) RoundOrder(side Side, rate, amount decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	places := int32(info.DecimalPlaces)
+	if side == Sell {
+		rate = rate.RoundCeil(places)
+	} else {
+		rate = rate.RoundFloor(places)
+	}
+	return rate, amount.Truncate(amountDecimalPlaces)
+}
+
+// CheckOrder returns ValidationError if rate or amount
+// doesn't fit into the precision and limits of the pair.
+func (info PairInfo) CheckOrder(pair Pair, rate, amount decimal.Decimal) error {
+	fail := func(field string, value decimal.Decimal, format string, args ...interface{}) error {
+		return &ValidationError{
+			Pair:   pair,
+			Field:  field,
+			Value:  value,
+			Reason: fmt.Sprintf(format, args...),
+		}
+	}
+
+	if !rate.Equal(rate.Round(int32(info.DecimalPlaces))) {
+		return fail("rate", rate, "has more than %d decimal places", info.DecimalPlaces)
+	}
+	if rate.LessThan(info.MinPrice) {
+		return fail("rate", rate, "is less than min price %s", info.MinPrice)
+	}
+	if !info.MaxPrice.IsZero() && rate.GreaterThan(info.MaxPrice) {
+		return fail("rate", rate, "is greater than max price %s", info.MaxPrice)
+	}
+	if !amount.Equal(amount.Truncate(amountDecimalPlaces)) {
+		return fail("amount", amount, "has more than %d decimal places", amountDecimalPlaces)
+	}
+	if amount.LessThan(info.MinAmount) {
+		return fail("amount", amount, "is less than min amount %s", info.MinAmount)
+	}
+
+	return nil
+}
+
+// orderValidator checks orders against cached pairs info.
+type orderValidator struct {
+	cli   *Client
+	round bool
+	ttl   time.Duration
+
+	mu       sync.Mutex
+	pairs    map[string]PairInfo
+	fetched  time.Time
+	fetching chan struct{} // closed when Info request is done
+}
+
+func (v *orderValidator) validate(ctx context.Context, pair Pair, side Side, rate, amount decimal.Decimal) (decimal.Decimal, decimal.Decimal, error) {
+	pairs, err := v.pairsInfo(ctx)
+	if err != nil {
+		return rate, amount, errors.Wrap(err, "pairs info")
+	}
+
+	info, ok := pairs[pair.String()]
+	if !ok {
+		return rate, amount, &ValidationError{
+			Pair:   pair,
+			Reason: "pair is not traded",
+		}
+	}
+
+	if v.round {
+		rate, amount = info.RoundOrder(side, rate, amount)
+	}
+
+	return rate, amount, info.CheckOrder(pair, rate, amount)
+}
+
+// pairsInfo returns cached pairs info, requesting Info if
+// it's expired. Only one request is made at a time, others
+// wait for it until their ctx is done.
+func (v *orderValidator) pairsInfo(ctx context.Context) (map[string]PairInfo, error) {
+	for {
+		v.mu.Lock()
+		if v.pairs != nil && time.Since(v.fetched) < v.ttl {
+			pairs := v.pairs
+			v.mu.Unlock()
+			return pairs, nil
+		}
+
+		fetching := v.fetching
+		if fetching == nil {
+			break
+		}
+		v.mu.Unlock()
+
+		// Request again if the awaited one failed.
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-fetching:
+		}
+	}
+
+	fetching := make(chan struct{})
+	v.fetching = fetching
+	v.mu.Unlock()
+
+	info, err := v.cli.InfoContext(ctx)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	close(fetching)
+	v.fetching = nil
+
+	if err != nil {
+		return nil, err
+	}
+
+	v.pairs, v.fetched = info.Pairs, time.Now()
+	return v.pairs, nil
+}
@@ -0,0 +1,195 @@
+package wexapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPairInfo_CheckOrder(t *testing.T) {
+	info := PairInfo{
+		DecimalPlaces: 3,
+		MinPrice:      decimal.RequireFromString("0.1"),
+		MaxPrice:      decimal.RequireFromString("400"),
+		MinAmount:     decimal.RequireFromString("0.01"),
+	}
+
+	tests := []struct {
+		name      string
+		rate      string
+		amount    string
+		wantField string
+	}{
+		{name: "valid", rate: "100.125", amount: "0.5"},
+		{name: "rate precision", rate: "100.1255", amount: "0.5", wantField: "rate"},
+		{name: "rate below min", rate: "0.01", amount: "0.5", wantField: "rate"},
+		{name: "rate above max", rate: "400.001", amount: "0.5", wantField: "rate"},
+		{name: "amount precision", rate: "100", amount: "0.123456789", wantField: "amount"},
+		{name: "amount below min", rate: "100", amount: "0.001", wantField: "amount"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := info.CheckOrder(NewPair("btc", "usd"), decimal.RequireFromString(tt.rate), decimal.RequireFromString(tt.amount))
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("PairInfo.CheckOrder() error = %v", err)
+				}
+				return
+			}
+
+			validationErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("PairInfo.CheckOrder() error = %v, want ValidationError", err)
+			}
+			if validationErr.Field != tt.wantField {
+				t.Errorf("ValidationError.Field = %s, want %s", validationErr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestClient_TradeWithValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		round      bool
+		pair       Pair
+		side       Side
+		rate       string
+		amount     string
+		wantRate   string
+		wantAmount string
+		wantErr    bool
+	}{
+		{
+			name:       "valid",
+			pair:       NewPair("btc", "usd"),
+			side:       Buy,
+			rate:       "100.5",
+			amount:     "0.1",
+			wantRate:   "100.5",
+			wantAmount: "0.1",
+		},
+		{
+			name:    "rejected",
+			pair:    NewPair("btc", "usd"),
+			side:    Buy,
+			rate:    "100.5555",
+			amount:  "0.1",
+			wantErr: true,
+		},
+		{
+			name:       "buy rounded down",
+			round:      true,
+			pair:       NewPair("btc", "usd"),
+			side:       Buy,
+			rate:       "100.5555",
+			amount:     "0.123456789",
+			wantRate:   "100.555",
+			wantAmount: "0.12345678",
+		},
+		{
+			name:       "sell rounded up",
+			round:      true,
+			pair:       NewPair("btc", "usd"),
+			side:       Sell,
+			rate:       "100.5551",
+			amount:     "0.123456789",
+			wantRate:   "100.556",
+			wantAmount: "0.12345678",
+		},
+		{
+			name:    "unknown pair",
+			pair:    NewPair("ltc", "usd"),
+			side:    Buy,
+			rate:    "100",
+			amount:  "1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var infoCalls, tradeCalls int
+			var gotRate, gotAmount string
+			server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/3/info" {
+					infoCalls++
+					fmt.Fprint(w, infoResponse)
+					return
+				}
+				tradeCalls++
+				gotRate, gotAmount = r.FormValue("rate"), r.FormValue("amount")
+				fmt.Fprint(w, tradeResponse)
+			}))
+			defer server.Close()
+			httpClient := testingHTTPClient(server)
+
+			cli := NewClient("", "", SetHTTPClient(httpClient), SetOrderValidation(tt.round))
+			for i := 0; i < 2; i++ {
+				_, err := cli.Trade(tt.pair, tt.side, decimal.RequireFromString(tt.rate), decimal.RequireFromString(tt.amount))
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("Client.Trade() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+
+			if infoCalls != 1 {
+				t.Errorf("info requested %d times, want 1", infoCalls)
+			}
+			if tt.wantErr {
+				if tradeCalls != 0 {
+					t.Errorf("invalid order sent %d times", tradeCalls)
+				}
+				return
+			}
+			if gotRate != tt.wantRate || gotAmount != tt.wantAmount {
+				t.Errorf("sent rate %s amount %s, want rate %s amount %s", gotRate, gotAmount, tt.wantRate, tt.wantAmount)
+			}
+		})
+	}
+}
+
+func TestOrderValidator_pairsInfo(t *testing.T) {
+	var infoCalls int32
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := createFakeServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&infoCalls, 1) == 2 {
+			entered <- struct{}{}
+			<-release
+		}
+		fmt.Fprint(w, infoResponse)
+	}))
+	defer server.Close()
+	defer close(release)
+	httpClient := testingHTTPClient(server)
+
+	cli := NewClient("", "", SetHTTPClient(httpClient), SetOrderValidation(false))
+	validator := cli.orderValidator
+	validator.ttl = 20 * time.Millisecond
+
+	if _, err := validator.pairsInfo(context.Background()); err != nil {
+		t.Fatalf("orderValidator.pairsInfo() error = %v", err)
+	}
+	if _, err := validator.pairsInfo(context.Background()); err != nil {
+		t.Fatalf("orderValidator.pairsInfo() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&infoCalls); got != 1 {
+		t.Errorf("info requested %d times before expiration, want 1", got)
+	}
+
+	// Expired info is requested again, the waiting
+	// call gives up when its ctx is done.
+	time.Sleep(30 * time.Millisecond)
+	go validator.pairsInfo(context.Background())
+	<-entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := validator.pairsInfo(ctx); err != context.DeadlineExceeded {
+		t.Errorf("orderValidator.pairsInfo() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}